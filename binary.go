@@ -0,0 +1,115 @@
+package units
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// BinaryPrefixes are IEC binary prefixes for encoding and decoding data sizes,
+// each prefix is the associated power of 1024 (ie. Ki = 1024^1)
+var BinaryPrefixes = []string{"", "Ki", "Mi", "Gi", "Ti", "Pi"}
+
+var binaryPrefixMap map[string]int
+var binaryOnce sync.Once
+
+// binaryPrefixes lazily builds the binary prefix map on first use
+func binaryPrefixes() map[string]int {
+	binaryOnce.Do(func() {
+		binaryPrefixMap = make(map[string]int)
+		for i := range BinaryPrefixes {
+			binaryPrefixMap[BinaryPrefixes[i]] = i
+		}
+	})
+	return binaryPrefixMap
+}
+
+// MarshalBinary is a helper for binary (IEC) unit serialisation/marshalling, ie. `1.00 KiB`,
+// formatted as configured by DefaultFormatter
+func MarshalBinary(unit string, value float64) ([]byte, error) {
+	if err := checkUnit(unit); err != nil {
+		return nil, err
+	}
+	return DefaultFormatter.appendBinary(nil, unit, value)
+}
+
+// appendBinary appends the value scaled to the nearest binary (IEC) prefix to dst,
+// returning dst unchanged on error
+func (f Formatter) appendBinary(dst []byte, unit string, value float64) ([]byte, error) {
+	if f.Precision < 0 {
+		return dst, fmt.Errorf("Invalid precision: %d (must be non-negative)", f.Precision)
+	}
+	if err := checkFinite(value); err != nil {
+		return dst, err
+	}
+
+	// Select the largest prefix that the value reaches, fixed notation leaves scaling to the consumer
+	power := 0
+	if f.Notation != NotationFixed {
+		for scaled := math.Abs(value); scaled >= 1024 && power < len(BinaryPrefixes)-1; scaled = scaled / 1024 {
+			power++
+		}
+	}
+
+	var buf [32]byte
+	mantissa := value / math.Pow(1024, float64(power))
+	digits := f.Rounding.appendRound(buf[:0], math.Abs(mantissa), f.Precision)
+
+	// Rounding may carry the mantissa to the next prefix (ie. 1023.999 -> 1024.00),
+	// in which case the next prefix is used to keep the output within [1, 1024)
+	rounded, _ := strconv.ParseFloat(string(digits), 64)
+	if rounded >= 1024 && f.Notation != NotationFixed && power < len(BinaryPrefixes)-1 {
+		power++
+		mantissa = value / math.Pow(1024, float64(power))
+		digits = f.Rounding.appendRound(buf[:0], math.Abs(mantissa), f.Precision)
+	}
+
+	return f.appendParts(dst, math.Signbit(mantissa), digits, BinaryPrefixes[power], unit), nil
+}
+
+// MarshalAuto is a helper for unit serialisation/marshalling selecting the prefixes by unit, using
+// MarshalBinary for data sizes registered with RegisterBinaryUnit (ie. `1.00 KiB`) and MarshalUnit
+// otherwise (ie. `1.02 kHz`)
+func MarshalAuto(unit string, value float64) ([]byte, error) {
+	if isBinaryUnit(unit) {
+		return MarshalBinary(unit, value)
+	}
+	return MarshalUnit(unit, value)
+}
+
+// UnmarshalBinary is a helper for binary (IEC) unit deserialisation/unmarshalling.
+// Decimal (SI) prefixes are also accepted, so `1 KiB` is 1024 while `1 KB` is 1000.
+func UnmarshalBinary(unit string, text []byte) (float64, error) {
+
+	// Scan to check for sane strings
+	valueString, unitString, ok := matchUnit(text)
+	if !ok {
+		return 0.0, fmt.Errorf("%w: Unit must be of the form 'Value PrefixUnit`, ie. '100.2 Ki%s'", ErrMalformedValue, unit)
+	}
+
+	// Check suffix matches
+	if !strings.HasSuffix(unitString, unit) {
+		return 0.0, fmt.Errorf("%w: Unable to parse unit: '%s' expected suffix: '%s'", ErrUnitMismatch, unitString, unit)
+	}
+
+	// Strip suffix and calculate multiplier from binary or decimal prefix
+	prefix := strings.TrimSuffix(unitString, unit)
+	multiplier := 1.0
+	if power, ok := binaryPrefixes()[prefix]; ok {
+		multiplier = math.Pow(1024, float64(power))
+	} else if order, ok := siPrefixes().order(prefix); ok {
+		multiplier = math.Pow(10, float64(order))
+	} else {
+		return 0.0, fmt.Errorf("%w: Unrecognised binary prefix: '%s' (options: %s)", ErrUnknownPrefix, prefix, strings.Join(BinaryPrefixes, ", "))
+	}
+
+	// Parse floating point component
+	base, err := strconv.ParseFloat(valueString, 64)
+	if err != nil {
+		return 0.0, fmt.Errorf("%w: %v", ErrMalformedValue, err)
+	}
+
+	return base * multiplier, nil
+}
@@ -0,0 +1,86 @@
+package units
+
+import "testing"
+
+func TestMarshalBinary(t *testing.T) {
+	for _, c := range []struct {
+		v    float64
+		want string
+	}{{512, "512.00 B"}, {1024, "1.00 KiB"}, {1536, "1.50 KiB"}, {3 * 1024 * 1024 * 1024, "3.00 GiB"}, {0, "0.00 B"}} {
+		b, _ := MarshalBinary("B", c.v)
+		if string(b) != c.want {
+			t.Errorf("%v %q", c.v, b)
+		}
+		v, err := UnmarshalBinary("B", b)
+		if err != nil || v != c.v {
+			t.Errorf("%v %v", v, err)
+		}
+	}
+	if v, _ := UnmarshalBinary("B", []byte("1 kB")); v != 1000 {
+		t.Error(v)
+	}
+	if v, _ := UnmarshalBinary("B", []byte("1 KiB")); v != 1024 {
+		t.Error(v)
+	}
+	if _, err := UnmarshalUnit("B", []byte("1 KiB")); err == nil {
+		t.Error("collide")
+	}
+}
+
+func TestMarshalAuto(t *testing.T) {
+	cases := []struct {
+		unit  string
+		value float64
+		want  string
+	}{
+		{"B", 1024, "1.00 KiB"},
+		{"B", 1536 * 1024, "1.50 MiB"},
+		{"b", 2048, "2.00 Kib"},
+		{"Hz", 1024, "1.02 kHz"},
+		{"Hz", 1e6, "1.00 MHz"},
+	}
+	for _, c := range cases {
+		got, err := MarshalAuto(c.unit, c.value)
+		if err != nil || string(got) != c.want {
+			t.Errorf("%s %v: %q %v", c.unit, c.value, got, err)
+		}
+	}
+	if got, _ := MarshalAuto("o", 2048); string(got) != "2.05 ko" {
+		t.Error(string(got))
+	}
+	RegisterBinaryUnit("o")
+	if got, _ := MarshalAuto("o", 2048); string(got) != "2.00 Kio" {
+		t.Error(string(got))
+	}
+}
+
+func TestMarshalBinaryCarry(t *testing.T) {
+	cases := map[float64]string{
+		1023.999:    "1.00 KiB",
+		1023.994:    "1023.99 B",
+		1048575.999: "1.00 MiB",
+		-1023.999:   "-1.00 KiB",
+		1024 * 1024: "1.00 MiB",
+	}
+	for v, want := range cases {
+		if got, err := MarshalBinary("B", v); err != nil || string(got) != want {
+			t.Errorf("%v: %q want %q (%v)", v, got, want, err)
+		}
+	}
+}
+
+func TestMarshalBinaryFormatter(t *testing.T) {
+	saved := DefaultFormatter
+	defer func() { DefaultFormatter = saved }()
+	DefaultFormatter.Precision = 1
+	DefaultFormatter.NoSpace = true
+	for unit, want := range map[string]string{"B": "1.5KiB", "Hz": "1.5kHz"} {
+		if got, err := MarshalAuto(unit, 1536); err != nil || string(got) != want {
+			t.Errorf("%s: %q want %q (%v)", unit, got, want, err)
+		}
+	}
+	DefaultFormatter.Notation = NotationFixed
+	if got, _ := MarshalBinary("B", 2048); string(got) != "2048.0B" {
+		t.Error(string(got))
+	}
+}
@@ -0,0 +1,103 @@
+package units
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+)
+
+// defaultCacheSize is the capacity of a zero value CachingParser
+const defaultCacheSize = 128
+
+// CachingParser is a Parser with a least recently used cache of parse results keyed by input
+// text and Parser configuration, for workloads where identical strings recur. It is safe for
+// concurrent use, though the Parser configuration must not be modified concurrently with Parse.
+// The zero value is ready to use with a capacity of 128 results.
+type CachingParser struct {
+	Parser
+
+	mu      sync.Mutex
+	size    int
+	entries map[cacheKey]*list.Element
+	order   *list.List
+}
+
+// cacheKey identifies a cached parse result, including the configuration so results
+// cached before the Parser is modified are not returned
+type cacheKey struct {
+	parser Parser
+	text   string
+}
+
+// cacheEntry is a cached parse result
+type cacheEntry struct {
+	key    cacheKey
+	value  float64
+	prefix string
+	unit   string
+}
+
+// NewCachingParser creates a CachingParser retaining up to size results, evicting the least
+// recently used beyond that. NewCachingParser panics if size is not positive.
+func NewCachingParser(size int) *CachingParser {
+	if size < 1 {
+		panic(fmt.Sprintf("units: NewCachingParser: invalid size %d (must be positive)", size))
+	}
+	return &CachingParser{
+		size:    size,
+		entries: make(map[cacheKey]*list.Element, size),
+		order:   list.New(),
+	}
+}
+
+// init lazily initialises a zero value CachingParser, the caller must hold mu
+func (c *CachingParser) lazyInit() {
+	if c.entries != nil {
+		return
+	}
+	if c.size < 1 {
+		c.size = defaultCacheSize
+	}
+	c.entries = make(map[cacheKey]*list.Element, c.size)
+	c.order = list.New()
+}
+
+// Parse parses SI unit text as with Parser.Parse, returning cached results for repeated text.
+// Failed parses are not cached.
+func (c *CachingParser) Parse(text []byte) (value float64, prefix string, unit string, err error) {
+	key := cacheKey{parser: c.Parser, text: string(text)}
+
+	c.mu.Lock()
+	c.lazyInit()
+	if e, ok := c.entries[key]; ok {
+		c.order.MoveToFront(e)
+		entry := e.Value.(*cacheEntry)
+		c.mu.Unlock()
+		return entry.value, entry.prefix, entry.unit, nil
+	}
+	c.mu.Unlock()
+
+	value, prefix, unit, err = key.parser.Parse(text)
+	if err != nil {
+		return 0.0, "", "", err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Another caller may have cached the same text while parsing
+	if e, ok := c.entries[key]; ok {
+		c.order.MoveToFront(e)
+		return value, prefix, unit, nil
+	}
+
+	entry := &cacheEntry{key: key, value: value, prefix: prefix, unit: unit}
+	c.entries[key] = c.order.PushFront(entry)
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+
+	return value, prefix, unit, nil
+}
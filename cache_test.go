@@ -0,0 +1,87 @@
+package units
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestCachingParser(t *testing.T) {
+	c := NewCachingParser(4)
+	for i := 0; i < 3; i++ {
+		v, p, u, err := c.Parse([]byte("3.3 mV"))
+		if err != nil || v != 0.0033 || p != "m" || u != "V" {
+			t.Fatal(v, p, u, err)
+		}
+	}
+	if len(c.entries) != 1 {
+		t.Fatal(len(c.entries))
+	}
+	for i := 0; i < 20; i++ {
+		if _, _, _, err := c.Parse([]byte(fmt.Sprintf("%d Hz", i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(c.entries) != 4 || c.order.Len() != 4 {
+		t.Fatal(len(c.entries))
+	}
+	if _, ok := c.entries[cacheKey{text: "19 Hz"}]; !ok {
+		t.Fatal("missing recent")
+	}
+	if _, _, _, err := c.Parse([]byte("bad")); err == nil {
+		t.Fatal("expected error")
+	}
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				text := fmt.Sprintf("%d KHz", (i+g)%7)
+				v, _, _, err := c.Parse([]byte(text))
+				if err != nil || v != float64((i+g)%7)*1000 {
+					t.Error(text, v, err)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	if len(c.entries) > 4 {
+		t.Fatal(len(c.entries))
+	}
+}
+
+func TestCachingParserZeroValue(t *testing.T) {
+	var c CachingParser
+	for i := 0; i < defaultCacheSize+10; i++ {
+		v, _, _, err := c.Parse([]byte(fmt.Sprintf("%d mV", i)))
+		if err != nil || !(Unit{"V", v}).EqualTol(Unit{"V", float64(i) * 1e-3}, 1e-12) {
+			t.Fatal(i, v, err)
+		}
+	}
+	if len(c.entries) != defaultCacheSize {
+		t.Fatal(len(c.entries))
+	}
+}
+
+func TestCachingParserConfigChange(t *testing.T) {
+	c := NewCachingParser(4)
+	if v, _, _, err := c.Parse([]byte("1,234 V")); err == nil {
+		t.Fatal("expected error without grouping", v)
+	}
+	c.GroupSep = ','
+	if v, _, _, err := c.Parse([]byte("1,234 V")); err != nil || v != 1234 {
+		t.Fatal(v, err)
+	}
+
+	// Results cached under a previous configuration are not reused
+	c.GroupSep = 0
+	if _, _, _, err := c.Parse([]byte("1,234 V")); err == nil {
+		t.Fatal("expected stale result to be ignored")
+	}
+	c.RequireKnown = true
+	if _, _, _, err := c.Parse([]byte("3 Xyz")); !errors.Is(err, ErrUnknownUnit) {
+		t.Fatal(err)
+	}
+}
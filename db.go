@@ -0,0 +1,73 @@
+package units
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// LogUnits are the logarithmic (decibel) units supported by MarshalDB and UnmarshalDB
+var LogUnits = []string{"dB", "dBm", "dBmV", "dBW"}
+
+// checkLogUnit returns an error if the unit is not one of LogUnits
+func checkLogUnit(unit string) error {
+	for _, u := range LogUnits {
+		if u == unit {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: Unsupported logarithmic unit: '%s' (options: %s)", ErrUnitMismatch, unit, strings.Join(LogUnits, ", "))
+}
+
+// MarshalDB is a helper for logarithmic (decibel) unit serialisation/marshalling, ie. `10.20 dBmV`.
+// Values are logarithmic quantities so no SI prefix scaling is applied.
+func MarshalDB(unit string, value float64) ([]byte, error) {
+	if err := checkLogUnit(unit); err != nil {
+		return nil, err
+	}
+
+	f := DefaultFormatter
+	f.Notation = NotationFixed
+	return f.append(nil, unit, value)
+}
+
+// UnmarshalDB is a helper for logarithmic (decibel) unit deserialisation/unmarshalling, ie. `-3 dBm`.
+// The unit must match exactly, so the `m` in `dBm` is never read as a milli prefix.
+func UnmarshalDB(unit string, text []byte) (float64, error) {
+	if err := checkLogUnit(unit); err != nil {
+		return 0.0, err
+	}
+
+	valueString, unitString, ok := matchUnit(text)
+	if !ok {
+		return 0.0, fmt.Errorf("%w: Unit must be of the form 'Value Unit`, ie. '10.2 %s'", ErrMalformedValue, unit)
+	}
+	if unitString != unit {
+		return 0.0, fmt.Errorf("%w: Unable to parse unit: '%s' expected: '%s'", ErrUnitMismatch, unitString, unit)
+	}
+
+	value, err := strconv.ParseFloat(valueString, 64)
+	if err != nil {
+		return 0.0, fmt.Errorf("%w: %v", ErrMalformedValue, err)
+	}
+
+	return value, nil
+}
+
+// Ratio returns the linear ratio of a value to a reference, ie. 2 for (2, 1).
+// A zero reference produces an infinite (or NaN for a zero value) ratio.
+func Ratio(value, reference float64) float64 {
+	return value / reference
+}
+
+// RatioDB returns the ratio of a value to a reference in decibels `20*log10(value/reference)`,
+// ie. ~6.02 dB for (2, 1). Where the ratio is undefined or not positive (ie. a zero or negative
+// reference or value) the result is -Inf.
+func RatioDB(value, reference float64) float64 {
+	ratio := Ratio(value, reference)
+	if reference <= 0 || !(ratio > 0) {
+		return math.Inf(-1)
+	}
+	return 20 * math.Log10(ratio)
+}
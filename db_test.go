@@ -0,0 +1,62 @@
+package units
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestMarshalDB(t *testing.T) {
+	cases := []struct {
+		unit string
+		v    float64
+		text string
+	}{
+		{"dB", 3, "3.00 dB"}, {"dBm", -30.5, "-30.50 dBm"}, {"dBmV", 10.2, "10.20 dBmV"}, {"dBW", 12000, "12000.00 dBW"},
+	}
+	for _, c := range cases {
+		got, err := MarshalDB(c.unit, c.v)
+		if err != nil || string(got) != c.text {
+			t.Errorf("%v: %q %v", c.v, got, err)
+		}
+		back, err := UnmarshalDB(c.unit, got)
+		if err != nil || back != c.v {
+			t.Errorf("%q: %v %v", got, back, err)
+		}
+	}
+	if v, err := UnmarshalDB("dBm", []byte("-3dBm")); err != nil || v != -3 {
+		t.Error(v, err)
+	}
+	if _, err := UnmarshalDB("dBm", []byte("3 dB")); !errors.Is(err, ErrUnitMismatch) {
+		t.Error(err)
+	}
+	if _, err := UnmarshalDB("dB", []byte("3 mdB")); !errors.Is(err, ErrUnitMismatch) {
+		t.Error(err)
+	}
+	if _, err := MarshalDB("V", 1); !errors.Is(err, ErrUnitMismatch) {
+		t.Error(err)
+	}
+}
+
+func TestRatio(t *testing.T) {
+	if r := Ratio(2, 1); r != 2 {
+		t.Error(r)
+	}
+	if r := Ratio(1, 0); !math.IsInf(r, 1) {
+		t.Error(r)
+	}
+	if d := RatioDB(2, 1); math.Abs(d-6.0206) > 1e-4 {
+		t.Error(d)
+	}
+	if d := RatioDB(10, 1); d != 20 {
+		t.Error(d)
+	}
+	if d := RatioDB(0.001, 1); d != -60 {
+		t.Error(d)
+	}
+	for _, c := range [][2]float64{{1, 0}, {1, -1}, {0, 1}, {-1, 1}, {0, 0}} {
+		if d := RatioDB(c[0], c[1]); !math.IsInf(d, -1) {
+			t.Error(c, d)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package units
+
+import (
+	"errors"
+)
+
+var (
+	// ErrMalformedValue is returned when text is not of the form `Value PrefixUnit`
+	ErrMalformedValue = errors.New("malformed value")
+	// ErrUnknownPrefix is returned when a prefix is not recognised
+	ErrUnknownPrefix = errors.New("unknown prefix")
+	// ErrUnitMismatch is returned when the parsed unit does not match the expected unit
+	ErrUnitMismatch = errors.New("unit mismatch")
+	// ErrLossyFormat is returned when strict formatting would lose accuracy
+	ErrLossyFormat = errors.New("lossy format")
+	// ErrNotFinite is returned when attempting to format NaN or infinite values
+	ErrNotFinite = errors.New("value not finite")
+	// ErrOutOfRange is returned when a value is beyond the smallest or largest supported prefix
+	ErrOutOfRange = errors.New("value out of range")
+	// ErrDimensionMismatch is returned when combining quantities of incompatible dimensions
+	ErrDimensionMismatch = errors.New("dimension mismatch")
+	// ErrUnknownUnit is returned when a detected unit symbol has not been registered as known
+	ErrUnknownUnit = errors.New("unknown unit")
+	// ErrPrefixMismatch is returned when the parsed prefix does not match the expected prefix
+	ErrPrefixMismatch = errors.New("prefix mismatch")
+	// ErrEmptyUnit is returned when marshalling with an empty unit, see MarshalScalar for dimensionless values
+	ErrEmptyUnit = errors.New("empty unit")
+)
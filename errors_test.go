@@ -0,0 +1,22 @@
+package units
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSentinelErrors(t *testing.T) {
+	for _, c := range []struct {
+		s   string
+		err error
+	}{{"3.3 mA", ErrUnitMismatch}, {"3.3 XV", ErrUnknownPrefix}, {"abc", ErrMalformedValue}, {"1.2.3 V", ErrMalformedValue}} {
+		_, err := UnmarshalUnit("V", []byte(c.s))
+		if !errors.Is(err, c.err) {
+			t.Errorf("%s: %v", c.s, err)
+		}
+	}
+	var u Unit
+	if err := u.UnmarshalText([]byte("!!")); !errors.Is(err, ErrMalformedValue) {
+		t.Error(err)
+	}
+}
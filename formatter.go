@@ -0,0 +1,480 @@
+package units
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Notation selects how values are scaled when formatting
+type Notation int
+
+const (
+	// NotationEngineering scales values to SI prefixes with exponents in multiples of 3, ie. `12.00 kHz`
+	NotationEngineering Notation = iota
+	// NotationFixed formats the raw value in fixed point with the bare unit, ie. `12000.00 Hz`
+	NotationFixed
+)
+
+// Case selects the casing of the unit symbol when formatting
+type Case int
+
+const (
+	// CaseAsIs emits the unit symbol unchanged
+	CaseAsIs Case = iota
+	// CaseUpper emits the unit symbol in upper case, ie. `3.30 mHZ`
+	CaseUpper
+	// CaseLower emits the unit symbol in lower case, ie. `3.30 mv`
+	CaseLower
+)
+
+// Formatter configures SI unit formatting for locale support
+type Formatter struct {
+	// DecimalSep is the decimal separator, defaults to "." if empty
+	DecimalSep string
+	// GroupSep is the thousands grouping separator, no grouping is applied if empty. Grouping only
+	// applies to mantissas of 1000 or more, ie. with NotationFixed or a MaxOrder, as engineering
+	// notation otherwise keeps mantissas below 1000.
+	GroupSep string
+	// Precision is the number of decimal places
+	Precision int
+	// Rounding is the rounding mode applied at the configured precision
+	Rounding RoundingMode
+	// MicroSign emits the micro sign `µ` rather than the ASCII `u` for the micro prefix
+	MicroSign bool
+	// NoSpace omits the space between the value and the prefixed unit, ie. `3.30mV` rather than `3.30 mV`
+	NoSpace bool
+	// Notation selects engineering (SI prefixed) or fixed point notation
+	Notation Notation
+	// UnitCase selects the casing of the unit symbol, the prefix is never modified
+	// as SI prefixes differ by case
+	UnitCase Case
+	// PadPrefix pads an empty prefix with a space so the unit column aligns with prefixed values,
+	// ie. `12.00  Hz` beneath `12.00 kHz`
+	PadPrefix bool
+	// TrimZeros trims insignificant trailing zeros and any trailing decimal separator after
+	// rounding to the configured precision, ie. `3.3 mV` rather than `3.30 mV`
+	TrimZeros bool
+	// PrefixAlias substitutes displayed prefixes, ie. `{"u": "µ"}` to emit `3.30 µV`.
+	// Aliases are applied to output only, the canonical prefixes are still accepted when parsing.
+	PrefixAlias map[string]string
+	// ClampPrefix formats values beyond the smallest or largest prefix with that prefix, ie.
+	// `5000.00 QHz`, rather than returning ErrOutOfRange
+	ClampPrefix bool
+	// Step is the exponent step between prefix orders for prefix selection, defaults to 3 if zero.
+	// Custom prefix tables registered with RegisterUnit on other steps (ie. 10^0, 10^2, 10^4)
+	// require the matching step.
+	Step int
+	// ForceSign emits a `+` sign for positive values, ie. `+3.30 mV`. Values that are zero
+	// (or round to zero) at the configured precision are unsigned either way, ie. `0.00 V`
+	// for -0.001 in fixed notation.
+	ForceSign bool
+	// MinOrder and MaxOrder constrain the selected prefix order where HasMinOrder and HasMaxOrder
+	// are set, ie. -3 and 9 to select between milli and giga or a MinOrder of 0 to never select a
+	// prefix below the base unit, with the mantissa extending beyond [1, 1000) at the limits.
+	// The orders must be multiples of the Step.
+	MinOrder    int64
+	MaxOrder    int64
+	HasMinOrder bool
+	HasMaxOrder bool
+	// Spelled spells out prefixes using PrefixWords and units using UnitWords where defined,
+	// ie. `12.00 kilohertz`, falling back to the symbols otherwise. This is output only.
+	Spelled bool
+	// UnitWords maps unit symbols to words for spelled output, ie. `{"Hz": "hertz"}`
+	UnitWords map[string]string
+}
+
+// PrefixWords are the words for SI prefixes used in spelled output, ie. `kilo` for `k`
+var PrefixWords = map[string]string{
+	"q": "quecto", "r": "ronto", "y": "yocto", "z": "zepto", "a": "atto", "f": "femto",
+	"p": "pico", "n": "nano", "u": "micro", "m": "milli",
+	"k": "kilo", "M": "mega", "G": "giga", "T": "tera", "P": "peta",
+	"E": "exa", "Z": "zetta", "Y": "yotta", "R": "ronna", "Q": "quetta",
+}
+
+// step returns the configured exponent step, defaulting to that of the SI prefixes
+func (f Formatter) step() int {
+	if f.Step <= 0 {
+		return defaultStep
+	}
+	return f.Step
+}
+
+// DefaultFormatter is the formatter used by MarshalUnit and related helpers, ie. `3.30 mV`.
+// It may be modified to configure formatting globally, though this is not goroutine safe
+// so should only be done during initialisation.
+var DefaultFormatter = Formatter{Precision: 2}
+
+// Format formats a value with the provided unit, falling back to plain formatting
+// if the value cannot be represented with an SI prefix
+func (f Formatter) Format(unit string, value float64) string {
+	str, err := f.format(unit, value)
+	if err != nil {
+		return fmt.Sprintf("%g %s", value, unit)
+	}
+	return str
+}
+
+func (f Formatter) format(unit string, value float64) (string, error) {
+	text, err := f.append(nil, unit, value)
+	if err != nil {
+		return "", err
+	}
+	return string(text), nil
+}
+
+// append appends the formatted value and unit to dst, returning dst unchanged on error
+func (f Formatter) append(dst []byte, unit string, value float64) ([]byte, error) {
+	dst, _, _, err := f.appendDetailed(dst, unit, value)
+	return dst, err
+}
+
+// appendDetailed appends the formatted value and unit to dst as with append,
+// additionally returning the selected prefix and the (unrounded) scaled mantissa
+func (f Formatter) appendDetailed(dst []byte, unit string, value float64) ([]byte, string, float64, error) {
+	if f.Precision < 0 {
+		return dst, "", 0, fmt.Errorf("Invalid precision: %d (must be non-negative)", f.Precision)
+	}
+	if err := checkFinite(value); err != nil {
+		return dst, "", 0, err
+	}
+
+	var buf [32]byte
+
+	// Pseudo-units are scaled by a fixed factor rather than SI prefixes
+	if factor, ok := pseudoUnits[unit]; ok {
+		scaled := value * factor
+		digits := f.Rounding.appendRound(buf[:0], math.Abs(scaled), f.Precision)
+		return f.appendParts(dst, math.Signbit(scaled), digits, "", unit), "", scaled, nil
+	}
+
+	// Fixed notation leaves scaling to the consumer. Prefixes bind before the power of the leading
+	// component (ie. `km^2` is 10^6 m^2), so powered units are left unscaled in the base unit.
+	if f.Notation == NotationFixed || leadingPower(unit) != 1 {
+		digits := f.Rounding.appendRound(buf[:0], math.Abs(value), f.Precision)
+		return f.appendParts(dst, math.Signbit(value), digits, "", unit), "", value, nil
+	}
+
+	// Whole values are formatted with integer arithmetic where this is unambiguous
+	if f.Rounding == RoundHalfEven && f.step() == defaultStep && !f.HasMinOrder && !f.HasMaxOrder &&
+		value == math.Trunc(value) && math.Abs(value) < maxExactInteger {
+		if out, prefix, mantissa, ok := f.appendInteger(dst, unit, value); ok {
+			return out, prefix, mantissa, nil
+		}
+	}
+
+	return f.appendScaled(dst, unit, value)
+}
+
+// appendScaled appends the value scaled to the nearest prefix order using floating point rounding
+func (f Formatter) appendScaled(dst []byte, unit string, value float64) ([]byte, string, float64, error) {
+	if err := f.checkOrders(); err != nil {
+		return dst, "", 0, err
+	}
+
+	var buf [32]byte
+
+	// Scale value to the nearest prefix order, clamping to the configured range if enabled
+	table := tableFor(unit)
+	mantissa, order := scale(value, f.step())
+	lo, hi, constrained := f.orderRange(table)
+	if constrained && (order < lo || order > hi) {
+		if order < lo {
+			order = lo
+		} else {
+			order = hi
+		}
+		mantissa = value / math.Pow10(order)
+	}
+
+	// Round mantissa digits to the configured precision
+	digits := f.Rounding.appendRound(buf[:0], math.Abs(mantissa), f.Precision)
+
+	// Rounding may carry the mantissa into the next prefix order (ie. 999.999 -> 1000.00),
+	// in which case the next prefix is used to keep the output within [1, 10^step)
+	if integerDigits(digits) > f.step() && !(constrained && order >= hi) {
+		order += f.step()
+		mantissa = value / math.Pow10(order)
+		digits = f.Rounding.appendRound(buf[:0], math.Abs(mantissa), f.Precision)
+	}
+
+	// Find the associated prefix
+	prefix, ok := table.orderMap[order]
+	if !ok {
+		return dst, "", 0, fmt.Errorf("%w: Unsupported prefix for exponent 10^%d (range: 10^%d to 10^%d)", ErrOutOfRange, order, table.minOrder, table.maxOrder)
+	}
+	if f.MicroSign && prefix == "u" {
+		prefix = "µ"
+	}
+
+	return f.appendParts(dst, math.Signbit(mantissa), digits, prefix, unit), prefix, mantissa, nil
+}
+
+// orderRange returns the range of prefix orders for selection, from the prefix table where ClampPrefix
+// is set and constrained by MinOrder and MaxOrder, or false if selection is unconstrained
+func (f Formatter) orderRange(table *prefixTable) (lo, hi int, constrained bool) {
+	lo, hi = math.MinInt, math.MaxInt
+	if f.ClampPrefix {
+		lo, hi = table.minOrder, table.maxOrder
+	}
+	if f.HasMinOrder && int(f.MinOrder) > lo {
+		lo = int(f.MinOrder)
+	}
+	if f.HasMaxOrder && int(f.MaxOrder) < hi {
+		hi = int(f.MaxOrder)
+	}
+	return lo, hi, f.ClampPrefix || f.HasMinOrder || f.HasMaxOrder
+}
+
+// checkOrders returns an error if the MinOrder and MaxOrder constraints are not multiples of the step,
+// so could never be selected, or are reversed
+func (f Formatter) checkOrders() error {
+	step := int64(f.step())
+	if f.HasMinOrder && f.MinOrder%step != 0 {
+		return fmt.Errorf("Invalid MinOrder: %d (must be a multiple of the step %d)", f.MinOrder, step)
+	}
+	if f.HasMaxOrder && f.MaxOrder%step != 0 {
+		return fmt.Errorf("Invalid MaxOrder: %d (must be a multiple of the step %d)", f.MaxOrder, step)
+	}
+	if f.HasMinOrder && f.HasMaxOrder && f.MinOrder > f.MaxOrder {
+		return fmt.Errorf("Invalid MinOrder: %d (must not exceed MaxOrder %d)", f.MinOrder, f.MaxOrder)
+	}
+	return nil
+}
+
+// maxExactInteger bounds whole values eligible for the integer fast path,
+// beyond which not all integers are exactly representable as float64
+const maxExactInteger = 1 << 53
+
+// pow10Int are the powers of ten representable as int64 below maxExactInteger
+var pow10Int = [...]int64{1, 1e1, 1e2, 1e3, 1e4, 1e5, 1e6, 1e7, 1e8, 1e9, 1e10, 1e11, 1e12, 1e13, 1e14, 1e15}
+
+// appendInteger appends a whole value as with appendScaled using integer arithmetic,
+// avoiding floating point formatting. It returns ok = false where the result may differ
+// from appendScaled (exact rounding ties, carries into the next prefix, or unsupported
+// prefixes), in which case the caller should use the general path.
+func (f Formatter) appendInteger(dst []byte, unit string, value float64) ([]byte, string, float64, bool) {
+	n := int64(math.Abs(value))
+
+	// Count digits and snap down to the nearest prefix order
+	order := 0
+	for order+3 < len(pow10Int) && n >= pow10Int[order+3] {
+		order += 3
+	}
+
+	prefix, ok := tableFor(unit).orderMap[order]
+	if !ok {
+		return dst, "", 0, false
+	}
+
+	// Split into integer and fractional components at the prefix order
+	integer, fraction := n/pow10Int[order], n%pow10Int[order]
+
+	// Round the fractional component to the configured precision
+	fracDigits := order
+	if f.Precision < order {
+		div := pow10Int[order-f.Precision]
+		rem := fraction % div
+		fraction /= div
+		if rem*2 == div {
+			return dst, "", 0, false
+		} else if rem*2 > div {
+			fraction++
+		}
+		if fraction == pow10Int[f.Precision] {
+			fraction = 0
+			integer++
+		}
+		if integer >= 1000 {
+			return dst, "", 0, false
+		}
+		fracDigits = f.Precision
+	}
+
+	var buf [32]byte
+	digits := strconv.AppendInt(buf[:0], integer, 10)
+	if f.Precision > 0 {
+		digits = append(digits, '.')
+		if fracDigits > 0 {
+			// Left-pad the fractional component with zeros to its digit count
+			for i := fracDigits - 1; i > 0 && fraction < pow10Int[i]; i-- {
+				digits = append(digits, '0')
+			}
+			digits = strconv.AppendInt(digits, fraction, 10)
+		}
+		for i := fracDigits; i < f.Precision; i++ {
+			digits = append(digits, '0')
+		}
+	}
+
+	mantissa := value / math.Pow10(order)
+	return f.appendParts(dst, math.Signbit(value), digits, prefix, unit), prefix, mantissa, true
+}
+
+// appendWithPrefix appends the value formatted with the provided prefix rather than
+// automatically selecting one, returning dst unchanged on error
+func (f Formatter) appendWithPrefix(dst []byte, unit, prefix string, value float64) ([]byte, error) {
+	if f.Precision < 0 {
+		return dst, fmt.Errorf("Invalid precision: %d (must be non-negative)", f.Precision)
+	}
+	if err := checkFinite(value); err != nil {
+		return dst, err
+	}
+
+	table := tableFor(unit)
+	order, ok := table.order(prefix)
+	if !ok {
+		return dst, fmt.Errorf("%w: Unrecognised SI prefix: '%s' (options: %s)", ErrUnknownPrefix, prefix, strings.Join(table.prefixes, ", "))
+	}
+	if _, ok := table.prefixMap[prefix]; !ok {
+		prefix = canonicalPrefix(prefix)
+	}
+	if f.MicroSign && prefix == "u" {
+		prefix = "µ"
+	}
+
+	mantissa := value / math.Pow(10, float64(order*leadingPower(unit)))
+
+	var buf [32]byte
+	digits := f.Rounding.appendRound(buf[:0], math.Abs(mantissa), f.Precision)
+
+	return f.appendParts(dst, math.Signbit(mantissa), digits, prefix, unit), nil
+}
+
+// appendParts appends the formatted number, prefix, and unit to dst
+func (f Formatter) appendParts(dst []byte, negative bool, digits []byte, prefix, unit string) []byte {
+	if dst == nil {
+		dst = make([]byte, 0, len(digits)+len(prefix)+len(unit)+4)
+	}
+	if f.TrimZeros && bytes.IndexByte(digits, '.') >= 0 {
+		digits = bytes.TrimRight(digits, "0")
+		digits = bytes.TrimSuffix(digits, []byte("."))
+	}
+	dst = f.appendNumber(dst, negative, digits)
+	if !f.NoSpace {
+		dst = append(dst, ' ')
+	}
+	if word, ok := PrefixWords[canonicalPrefix(prefix)]; ok && f.Spelled {
+		prefix = word
+	} else if alias, ok := f.PrefixAlias[prefix]; ok {
+		prefix = alias
+	}
+	if word, ok := f.UnitWords[unit]; ok && f.Spelled {
+		unit = word
+	}
+	if prefix == "" && f.PadPrefix {
+		dst = append(dst, ' ')
+	}
+	dst = append(dst, prefix...)
+	switch f.UnitCase {
+	case CaseUpper:
+		dst = append(dst, strings.ToUpper(unit)...)
+	case CaseLower:
+		dst = append(dst, strings.ToLower(unit)...)
+	default:
+		dst = append(dst, unit...)
+	}
+	return dst
+}
+
+// integerDigits returns the number of integer digits in rounded decimal digits
+func integerDigits(digits []byte) int {
+	if i := bytes.IndexByte(digits, '.'); i >= 0 {
+		return i
+	}
+	return len(digits)
+}
+
+// appendNumber appends rounded decimal digits to dst with the sign and configured separators
+func (f Formatter) appendNumber(dst []byte, negative bool, digits []byte) []byte {
+	switch {
+	case f.ForceSign && len(bytes.Trim(digits, "0.")) == 0:
+		// Values rounding to zero are unsigned
+	case negative:
+		dst = append(dst, '-')
+	case f.ForceSign:
+		dst = append(dst, '+')
+	}
+
+	// Split integer and fractional components
+	n := integerDigits(digits)
+	integer, fraction := digits[:n], digits[n:]
+
+	// Apply thousands grouping to the integer component
+	if f.GroupSep != "" && len(integer) > 3 {
+		for i := range integer {
+			if i > 0 && (len(integer)-i)%3 == 0 {
+				dst = append(dst, f.GroupSep...)
+			}
+			dst = append(dst, integer[i])
+		}
+	} else {
+		dst = append(dst, integer...)
+	}
+
+	if len(fraction) > 0 {
+		if f.DecimalSep == "" {
+			dst = append(dst, '.')
+		} else {
+			dst = append(dst, f.DecimalSep...)
+		}
+		dst = append(dst, fraction[1:]...)
+	}
+
+	return dst
+}
+
+// FormatTable formats a column of values with a single shared prefix, right-aligned to equal width.
+// The shared prefix is that of the median order of the non-zero values, so outliers spanning many
+// orders keep the majority readable at the expense of large or small mantissas at the extremes.
+// Values that cannot be formatted (NaN or infinite) are rendered with strconv formatting and no prefix.
+// An empty unit formats dimensionless values as with MarshalScalar. FormatTable panics if the
+// precision is negative.
+func FormatTable(unit string, values []float64, precision int) []string {
+	if precision < 0 {
+		panic(fmt.Sprintf("units: FormatTable: invalid precision %d (must not be negative)", precision))
+	}
+	table := tableFor(unit)
+
+	// Find the median order of the finite, non-zero values
+	orders := make([]int, 0, len(values))
+	for _, v := range values {
+		if v != 0 && checkFinite(v) == nil {
+			_, order := clampScale(table, v, DefaultFormatter.step())
+			orders = append(orders, order)
+		}
+	}
+	sort.Ints(orders)
+	prefix := ""
+	if len(orders) > 0 {
+		prefix = table.orderMap[orders[(len(orders)-1)/2]]
+	}
+
+	// Format values with the shared prefix, which only fails for non-finite values
+	f := DefaultFormatter
+	f.Precision = precision
+	lines := make([]string, len(values))
+	width := 0
+	for i, v := range values {
+		text, err := f.appendWithPrefix(nil, unit, prefix, v)
+		if err != nil {
+			lines[i] = strings.TrimRight(strconv.FormatFloat(v, 'f', -1, 64)+" "+unit, " ")
+		} else {
+			lines[i] = strings.TrimRight(string(text), " ")
+		}
+		if n := utf8.RuneCountInString(lines[i]); n > width {
+			width = n
+		}
+	}
+
+	// Right-align to the widest value
+	for i := range lines {
+		lines[i] = strings.Repeat(" ", width-utf8.RuneCountInString(lines[i])) + lines[i]
+	}
+
+	return lines
+}
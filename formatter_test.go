@@ -0,0 +1,439 @@
+package units
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestFormatterSeparators(t *testing.T) {
+	if s := DefaultFormatter.Format("V", 0.0033); s != "3.30 mV" {
+		t.Error(s)
+	}
+	eu := Formatter{DecimalSep: ",", GroupSep: ".", Precision: 2}
+	if s := eu.Format("V", 0.0033); s != "3,30 mV" {
+		t.Error(s)
+	}
+	// Engineering mantissas are below 1000 so are never grouped
+	if s := eu.Format("V", 1234.56); s != "1,23 kV" {
+		t.Error(s)
+	}
+	eu.Notation = NotationFixed
+	if s := eu.Format("V", 1234.56); s != "1.234,56 V" {
+		t.Error(s)
+	}
+	us := Formatter{GroupSep: ",", Precision: 1, Notation: NotationFixed}
+	for v, w := range map[float64]string{1234567.89: "1,234,567.9 V", -999: "-999.0 V", 100: "100.0 V", -1234: "-1,234.0 V"} {
+		if s := us.Format("V", v); s != w {
+			t.Error(s, w)
+		}
+	}
+	us = Formatter{GroupSep: ",", Precision: 1, MaxOrder: 3, HasMaxOrder: true}
+	if s := us.Format("Hz", 1234567.89); s != "1,234.6 kHz" {
+		t.Error(s)
+	}
+	for _, v := range []float64{0.0033, -12000, 0, 5.555, -0.001} {
+		b, _ := MarshalUnit("V", v)
+		if s := DefaultFormatter.Format("V", v); s != string(b) {
+			t.Error(s, string(b))
+		}
+	}
+}
+
+func TestFormatTable(t *testing.T) {
+	got := FormatTable("V", []float64{0.0033, 0.012, 1.5, -0.0001}, 2)
+	want := []string{"   3.30 mV", "  12.00 mV", "1500.00 mV", "  -0.10 mV"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("%q", got)
+	}
+	if got := FormatTable("V", nil, 2); len(got) != 0 {
+		t.Error(got)
+	}
+	got = FormatTable("Hz", []float64{0, 12000, 1e9}, 1)
+	if !reflect.DeepEqual(got, []string{"      0.0 kHz", "     12.0 kHz", "1000000.0 kHz"}) {
+		t.Errorf("%q", got)
+	}
+	got = FormatTable("V", []float64{0.001, math.NaN(), math.Inf(-1)}, 2)
+	if !reflect.DeepEqual(got, []string{"1.00 mV", "  NaN V", " -Inf V"}) {
+		t.Errorf("%q", got)
+	}
+	got = FormatTable("", []float64{1000, 2500}, 2)
+	if !reflect.DeepEqual(got, []string{"1.00 k", "2.50 k"}) {
+		t.Errorf("%q", got)
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("expected panic for negative precision")
+			}
+		}()
+		FormatTable("V", []float64{0.001}, -1)
+	}()
+}
+
+func TestFormatterSpace(t *testing.T) {
+	if s := DefaultFormatter.Format("V", 0.0033); s != "3.30 mV" {
+		t.Error(s)
+	}
+	if s := (Formatter{Precision: 2}).Format("V", 3.3); s != "3.30 V" {
+		t.Error(s)
+	}
+	f := DefaultFormatter
+	f.NoSpace = true
+	if s := f.Format("V", 0.0033); s != "3.30mV" {
+		t.Error(s)
+	}
+	if s := f.Format("%", 0.5); s != "50.00%" {
+		t.Error(s)
+	}
+	if v, err := UnmarshalUnit("V", []byte(f.Format("V", 0.0033))); err != nil || v < 0.003299 || v > 0.003301 {
+		t.Error(v, err)
+	}
+}
+
+func TestFormatterNotation(t *testing.T) {
+	f := DefaultFormatter
+	if s := f.Format("Hz", 12000); s != "12.00 kHz" {
+		t.Error(s)
+	}
+	f.Notation = NotationFixed
+	if s := f.Format("Hz", 12000); s != "12000.00 Hz" {
+		t.Error(s)
+	}
+	f.GroupSep = ","
+	if s := f.Format("V", 0.0033); s != "0.00 V" {
+		t.Error(s)
+	}
+	if s := f.Format("V", -1234567.891); s != "-1,234,567.89 V" {
+		t.Error(s)
+	}
+}
+
+func TestDefaultFormatter(t *testing.T) {
+	saved := DefaultFormatter
+	defer func() { DefaultFormatter = saved }()
+	DefaultFormatter.Precision = 3
+	if b, _ := MarshalUnit("V", 0.0033); string(b) != "3.300 mV" {
+		t.Error(string(b))
+	}
+	DefaultFormatter.NoSpace = true
+	if s := Sprint("Hz", 12000); s != "12.000kHz" {
+		t.Error(s)
+	}
+	if b, _ := MarshalUnitWithPrefix("V", "m", 1, 1); string(b) != "1000.0mV" {
+		t.Error(string(b))
+	}
+}
+
+func TestFormatterUnitCase(t *testing.T) {
+	f := DefaultFormatter
+	f.UnitCase = CaseLower
+	if s := f.Format("V", 0.0033); s != "3.30 mv" {
+		t.Error(s)
+	}
+	if s := f.Format("V", 3300000); s != "3.30 Mv" {
+		t.Error(s)
+	}
+	f.UnitCase = CaseUpper
+	if s := f.Format("Hz", 0.0033); s != "3.30 mHZ" {
+		t.Error(s)
+	}
+	f.UnitCase = CaseAsIs
+	if s := f.Format("Hz", 0.0033); s != "3.30 mHz" {
+		t.Error(s)
+	}
+}
+
+func TestIntegerFastPathEquivalence(t *testing.T) {
+	check := func(f Formatter, v float64) {
+		fast, fp, fm, ok := f.appendInteger(nil, "Hz", v)
+		if !ok {
+			return
+		}
+		slow, sp, sm, err := f.appendScaled(nil, "Hz", v)
+		if err != nil || string(fast) != string(slow) || fp != sp || fm != sm {
+			t.Fatalf("precision %d value %v: fast %q (%s %v) slow %q (%s %v) %v", f.Precision, v, fast, fp, fm, slow, sp, sm, err)
+		}
+	}
+	r := rand.New(rand.NewSource(1))
+	for p := 0; p <= 6; p++ {
+		f := Formatter{Precision: p}
+		for v := -200000; v <= 200000; v++ {
+			check(f, float64(v))
+		}
+		for i := 0; i < 200000; i++ {
+			check(f, float64(r.Int63n(maxExactInteger)))
+			check(f, -float64(r.Int63n(1<<30)))
+		}
+	}
+	check(DefaultFormatter, math.Copysign(0, -1))
+	f := DefaultFormatter
+	if s, _ := f.format("Hz", math.Copysign(0, -1)); s != "-0.00 Hz" {
+		t.Fatal(s)
+	}
+	// tie case falls back to the general path
+	if _, _, _, ok := DefaultFormatter.appendInteger(nil, "Hz", 12345); ok {
+		t.Fatal("expected fallback")
+	}
+	if s, _ := MarshalUnit("Hz", 12345); string(s) != "12.34 kHz" {
+		t.Fatal(string(s))
+	}
+	if s, _ := MarshalUnit("Hz", 999999); string(s) != "1.00 MHz" {
+		t.Fatal(string(s))
+	}
+}
+
+func BenchmarkMarshalInteger(b *testing.B) {
+	b.ReportAllocs()
+	buf := make([]byte, 0, 32)
+	for i := 0; i < b.N; i++ {
+		buf, _ = DefaultFormatter.append(buf[:0], "Hz", 123456)
+	}
+}
+
+func BenchmarkMarshalIntegerGeneral(b *testing.B) {
+	b.ReportAllocs()
+	buf := make([]byte, 0, 32)
+	for i := 0; i < b.N; i++ {
+		buf, _, _, _ = DefaultFormatter.appendScaled(buf[:0], "Hz", 123456)
+	}
+}
+
+func TestFormatterPadPrefix(t *testing.T) {
+	f := DefaultFormatter
+	f.PadPrefix = true
+	want := len(f.Format("Hz", 12000))
+	for _, v := range []float64{12, 12000, 0.012, 12e6, 12e-6} {
+		if s := f.Format("Hz", v); len(s) != want {
+			t.Errorf("%v: %q", v, s)
+		}
+	}
+	if s := f.Format("Hz", 12); s != "12.00  Hz" {
+		t.Error(s)
+	}
+	if s := DefaultFormatter.Format("Hz", 12); s != "12.00 Hz" {
+		t.Error(s)
+	}
+}
+
+func TestFormatterTrimZeros(t *testing.T) {
+	f := DefaultFormatter
+	f.TrimZeros = true
+	cases := map[float64]string{
+		0.0033: "3.3 mV", 12: "12 V", 10.5: "10.5 V", 1.23: "1.23 V", 0: "0 V", 100: "100 V", -2000: "-2 kV",
+	}
+	for v, want := range cases {
+		if got := f.Format("V", v); got != want {
+			t.Errorf("%v: %q want %q", v, got, want)
+		}
+	}
+	f.Precision = 0
+	if got := f.Format("V", 100); got != "100 V" {
+		t.Error(got)
+	}
+	f.Precision, f.GroupSep, f.Notation = 2, ",", NotationFixed
+	if got := f.Format("V", 12000); got != "12,000 V" {
+		t.Error(got)
+	}
+}
+
+func TestFormatterPrefixAlias(t *testing.T) {
+	f := DefaultFormatter
+	f.PrefixAlias = map[string]string{"u": "µ", "k": "K"}
+	cases := map[float64]string{3.3e-6: "3.30 µV", 12000: "12.00 KV", 0.0033: "3.30 mV", 5: "5.00 V"}
+	for v, want := range cases {
+		got := f.Format("V", v)
+		if got != want {
+			t.Errorf("%v: %q want %q", v, got, want)
+		}
+	}
+	if v, err := UnmarshalUnit("V", []byte(f.Format("V", 3.3e-6))); err != nil || !(Unit{"V", v}).EqualTol(Unit{"V", 3.3e-6}, 1e-12) {
+		t.Error(v, err)
+	}
+	if v, err := UnmarshalUnit("V", []byte("12.00 kV")); err != nil || v != 12000 {
+		t.Error(v, err)
+	}
+	old := DefaultFormatter
+	defer func() { DefaultFormatter = old }()
+	DefaultFormatter.PrefixAlias = map[string]string{"u": "µ"}
+	if s, _ := MarshalUnit("V", 3.3e-6); string(s) != "3.30 µV" {
+		t.Error(string(s))
+	}
+}
+
+func TestFormatterClampPrefix(t *testing.T) {
+	if _, err := DefaultFormatter.format("Hz", 5e33); !errors.Is(err, ErrOutOfRange) {
+		t.Fatal(err)
+	}
+	f := DefaultFormatter
+	f.ClampPrefix = true
+	cases := map[float64]string{
+		5e33: "5000.00 QHz", 999.999e30: "1000.00 QHz", 5e-32: "0.05 qHz", 1e-40: "0.00 qHz", 12000: "12.00 kHz", 5e30: "5.00 QHz", -5e33: "-5000.00 QHz",
+	}
+	for v, want := range cases {
+		got, err := f.format("Hz", v)
+		if err != nil || got != want {
+			t.Errorf("%v: %q want %q (%v)", v, got, want, err)
+		}
+	}
+	RegisterUnit("clampy", []string{"", "K"}, []int64{0, 3})
+	if got, _ := f.format("clampy", 2e7); got != "20000.00 Kclampy" {
+		t.Error(got)
+	}
+	if got, _ := f.format("clampy", 0.5); got != "0.50 clampy" {
+		t.Error(got)
+	}
+}
+
+func TestFormatterStep(t *testing.T) {
+	RegisterUnit("b2", []string{"", "h", "w", "x"}, []int64{0, 2, 4, 6})
+	f := DefaultFormatter
+	f.Step = 2
+	cases := map[float64]string{
+		12345: "1.23 wb2", 5: "5.00 b2", 99.999: "1.00 hb2", 100: "1.00 hb2", 1234: "12.34 hb2", 3.4e6: "3.40 xb2",
+	}
+	for v, want := range cases {
+		got, err := f.format("b2", v)
+		if err != nil || got != want {
+			t.Errorf("%v: %q want %q (%v)", v, got, want, err)
+		}
+	}
+	if got, _ := f.format("b2", 0.5); got != "" {
+		t.Error(got)
+	}
+	if got := DefaultFormatter.Format("Hz", 12345); got != "12.34 kHz" {
+		t.Error(got)
+	}
+	f.Step = 6
+	if got := f.Format("Hz", 12345); got != "12345.00 Hz" {
+		t.Error(got)
+	}
+	if got := f.Format("Hz", 1.2e7); got != "12.00 MHz" {
+		t.Error(got)
+	}
+}
+
+func TestFormatterForceSign(t *testing.T) {
+	f := DefaultFormatter
+	f.ForceSign = true
+	cases := map[float64]string{0.0033: "+3.30 mV", -0.0033: "-3.30 mV", 0: "0.00 V", 12000: "+12.00 kV"}
+	for v, want := range cases {
+		if got := f.Format("V", v); got != want {
+			t.Errorf("%v: %q want %q", v, got, want)
+		}
+	}
+	f.ClampPrefix = true
+	if got := f.Format("V", 1e-34); got != "0.00 qV" {
+		t.Error(got)
+	}
+	if got := f.Format("V", -1e-34); got != "0.00 qV" {
+		t.Error(got)
+	}
+	f.ClampPrefix = false
+	f.Notation = NotationFixed
+	for _, v := range []float64{-0.001, math.Copysign(0, -1), 0.004} {
+		if got := f.Format("V", v); got != "0.00 V" {
+			t.Errorf("%v: %q", v, got)
+		}
+	}
+	if got := f.Format("V", -0.0051); got != "-0.01 V" {
+		t.Error(got)
+	}
+	if got := DefaultFormatter.Format("V", 1); got != "1.00 V" {
+		t.Error(got)
+	}
+}
+
+func TestFormatterMinMaxOrder(t *testing.T) {
+	f := DefaultFormatter
+	f.MinOrder, f.MaxOrder = -3, 9
+	f.HasMinOrder, f.HasMaxOrder = true, true
+	f.Precision = 6
+	cases := map[float64]string{
+		3.3e-12: "0.000000 mV", 4.7e-6: "0.004700 mV", 3.3e12: "3300.000000 GV", 12000: "12.000000 kV", 999.9999999e9: "1000.000000 GV", 0.5: "500.000000 mV",
+	}
+	for v, want := range cases {
+		got, err := f.format("V", v)
+		if err != nil || got != want {
+			t.Errorf("%v: %q want %q (%v)", v, got, want, err)
+		}
+	}
+	g := DefaultFormatter
+	g.MaxOrder, g.HasMaxOrder = 3, true
+	if got := g.Format("Hz", 5e9); got != "5000000.00 kHz" {
+		t.Error(got)
+	}
+	g = DefaultFormatter
+	g.MinOrder, g.HasMinOrder = 3, true
+	if got := g.Format("Hz", 12); got != "0.01 kHz" {
+		t.Error(got)
+	}
+	if got := g.Format("Hz", 1e-40); got != "0.00 kHz" {
+		t.Error(got)
+	}
+
+	// Zero orders constrain to the base unit
+	g = DefaultFormatter
+	g.MinOrder, g.HasMinOrder = 0, true
+	for v, want := range map[float64]string{0.5: "0.50 Hz", 0.001: "0.00 Hz", 12000: "12.00 kHz", 999.999: "1.00 kHz"} {
+		if got := g.Format("Hz", v); got != want {
+			t.Errorf("MinOrder 0: %v: %q want %q", v, got, want)
+		}
+	}
+	g = DefaultFormatter
+	g.MaxOrder, g.HasMaxOrder = 0, true
+	for v, want := range map[float64]string{12000: "12000.00 Hz", 0.5: "500.00 mHz", 999.999: "1000.00 Hz"} {
+		if got := g.Format("Hz", v); got != want {
+			t.Errorf("MaxOrder 0: %v: %q want %q", v, got, want)
+		}
+	}
+
+	// Orders must be selectable with the step and in order
+	for _, g := range []Formatter{
+		{Precision: 2, MinOrder: -4, HasMinOrder: true},
+		{Precision: 2, MaxOrder: 10, HasMaxOrder: true},
+		{Precision: 2, MinOrder: 6, MaxOrder: 3, HasMinOrder: true, HasMaxOrder: true},
+	} {
+		if _, err := g.format("Hz", 12); err == nil {
+			t.Errorf("%+v: expected error", g)
+		}
+	}
+	g = Formatter{Precision: 2, Step: 2, MinOrder: -4, HasMinOrder: true}
+	if _, err := g.format("Hz", 12); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestFormatterSpelled(t *testing.T) {
+	f := DefaultFormatter
+	f.Spelled = true
+	f.UnitWords = map[string]string{"Hz": "hertz", "V": "volts"}
+	cases := []struct {
+		unit string
+		v    float64
+		want string
+	}{
+		{"Hz", 12000, "12.00 kilohertz"},
+		{"V", 0.0033, "3.30 millivolts"},
+		{"V", 4.7e-6, "4.70 microvolts"},
+		{"Hz", 2.4e9, "2.40 gigahertz"},
+		{"Hz", 50, "50.00 hertz"},
+		{"Foo", 3000, "3.00 kiloFoo"},
+	}
+	for _, c := range cases {
+		if got := f.Format(c.unit, c.v); got != c.want {
+			t.Errorf("%v: %q want %q", c.v, got, c.want)
+		}
+	}
+	f.MicroSign = true
+	if got := f.Format("V", 4.7e-6); got != "4.70 microvolts" {
+		t.Error(got)
+	}
+	g := DefaultFormatter
+	g.UnitWords = f.UnitWords
+	if got := g.Format("Hz", 12000); got != "12.00 kHz" {
+		t.Error(got)
+	}
+}
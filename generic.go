@@ -0,0 +1,13 @@
+package units
+
+// Number is a constraint covering the integer and floating point kinds
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// MarshalUnitG is a generic wrapper around MarshalUnit for any numeric value
+func MarshalUnitG[T Number](unit string, value T) ([]byte, error) {
+	return MarshalUnit(unit, float64(value))
+}
@@ -0,0 +1,15 @@
+package units
+
+import "testing"
+
+func TestMarshalUnitG(t *testing.T) {
+	if b, _ := MarshalUnitG("Hz", 12000); string(b) != "12.00 kHz" {
+		t.Error(string(b))
+	}
+	if b, _ := MarshalUnitG("B", int64(2000000)); string(b) != "2.00 MB" {
+		t.Error(string(b))
+	}
+	if b, _ := MarshalUnitG("V", float32(0.5)); string(b) != "500.00 mV" {
+		t.Error(string(b))
+	}
+}
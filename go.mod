@@ -0,0 +1,3 @@
+module github.com/ryankurte/go-unit
+
+go 1.18
@@ -0,0 +1,68 @@
+package units
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// intRegex matches integer unit strings with optional hexadecimal (`0x`) or binary (`0b`) values,
+// ie. `0x1F counts` or `0b1010 bits`. A space is required after hexadecimal values where the unit
+// begins with a hexadecimal digit.
+var intRegex = regexp.MustCompile(`^([+\-]?(?:0[xX][0-9a-fA-F]+|0[bB][01]+|[0-9]+))[ ]{0,1}(` + unitPattern + `)$`)
+
+// UnmarshalInt is a helper for integer unit deserialisation/unmarshalling, accepting hexadecimal
+// (`0x1F`), binary (`0b1010`), and decimal values. SI prefixes are applied after parsing the value,
+// with prefixes smaller than the base unit rejected as they do not produce integers.
+func UnmarshalInt(unit string, text []byte) (int64, error) {
+	matches := intRegex.FindStringSubmatch(strings.TrimSpace(string(text)))
+	if matches == nil {
+		return 0, fmt.Errorf("%w: Unit must be of the form 'Value PrefixUnit`, ie. '0x1F k%s'", ErrMalformedValue, unit)
+	}
+	valueString, unitString := matches[1], matches[2]
+
+	// Check suffix matches and strip to find the prefix
+	prefix, ok := Parser{}.trimUnit(unitString, unit)
+	if !ok {
+		return 0, fmt.Errorf("%w: Unable to parse unit: '%s' expected suffix: '%s'", ErrUnitMismatch, unitString, unit)
+	}
+
+	// Calculate order from prefix
+	table := tableFor(unit)
+	order, ok := parseOrder(table, prefix)
+	if !ok || order < 0 {
+		return 0, fmt.Errorf("%w: Unrecognised integer prefix: '%s'", ErrUnknownPrefix, prefix)
+	}
+
+	// Detect the base from the value prefix
+	sign, digits := "", valueString
+	if digits[0] == '+' || digits[0] == '-' {
+		sign, digits = digits[:1], digits[1:]
+	}
+	base := 10
+	if len(digits) > 2 && digits[0] == '0' {
+		switch digits[1] {
+		case 'x', 'X':
+			base, digits = 16, digits[2:]
+		case 'b', 'B':
+			base, digits = 2, digits[2:]
+		}
+	}
+
+	value, err := strconv.ParseInt(sign+digits, base, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrMalformedValue, err)
+	}
+
+	// Multiply by prefix order, checking for overflow
+	for i := 0; i < order; i++ {
+		if value > math.MaxInt64/10 || value < math.MinInt64/10 {
+			return 0, fmt.Errorf("%w: Value '%s' overflows int64", ErrOutOfRange, text)
+		}
+		value *= 10
+	}
+
+	return value, nil
+}
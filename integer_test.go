@@ -0,0 +1,35 @@
+package units
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestUnmarshalInt(t *testing.T) {
+	for _, c := range []struct {
+		u, s string
+		v    int64
+	}{{"counts", "0x1F counts", 31}, {"bits", "0b1010 bits", 10}, {"bits", "0b1010bits", 10}, {"counts", "42 counts", 42}, {"counts", "010 counts", 10}, {"counts", "-0x10 Kcounts", -16000}, {"B", "0xFF B", 255}, {"m", "5 hm", 500}, {"counts", "3 dacounts", 30}} {
+		v, err := UnmarshalInt(c.u, []byte(c.s))
+		if err != nil || v != c.v {
+			t.Error(c, v, err)
+		}
+	}
+	if _, err := UnmarshalInt("counts", []byte("0x1F mcounts")); !errors.Is(err, ErrUnknownPrefix) {
+		t.Error(err)
+	}
+	for _, s := range []string{"5 cm", "5 dm"} {
+		if _, err := UnmarshalInt("m", []byte(s)); !errors.Is(err, ErrUnknownPrefix) {
+			t.Error(s, err)
+		}
+	}
+	if _, err := UnmarshalInt("counts", []byte("0xFFFFFFFFFFFFFFFFFF counts")); !errors.Is(err, ErrMalformedValue) {
+		t.Error(err)
+	}
+	if _, err := UnmarshalInt("counts", []byte("9 Qcounts")); !errors.Is(err, ErrOutOfRange) {
+		t.Error(err)
+	}
+	if _, err := UnmarshalInt("counts", []byte("1.5 counts")); !errors.Is(err, ErrMalformedValue) {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,240 @@
+package units
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// swappedRegex matches unit strings with the value and unit reversed, ie. `Hz 12000`
+var swappedRegex = regexp.MustCompile(`^(` + unitPattern + `)[ ]*(` + numberPattern + `)$`)
+
+// swappedHint returns a suggestion for text with the value and unit reversed, ie.
+// ` (did you mean '12000 Hz'?)` for `Hz 12000`, or an empty string otherwise
+func swappedHint(text []byte) string {
+	matches := swappedRegex.FindStringSubmatch(strings.TrimSpace(string(text)))
+	if matches == nil {
+		return ""
+	}
+	return fmt.Sprintf(" (did you mean '%s %s'?)", matches[2], matches[1])
+}
+
+// PrefixPolicy selects how unrecognised prefixes are handled when parsing
+type PrefixPolicy int
+
+const (
+	// PolicyError returns ErrUnknownPrefix for unrecognised prefixes
+	PolicyError PrefixPolicy = iota
+	// PolicyIgnore discards unrecognised prefixes, parsing the value as the base unit
+	PolicyIgnore
+)
+
+// Parser configures SI unit parsing
+type Parser struct {
+	// CaseInsensitive tolerates case variation in the unit symbol, ie. `3.3 mv` for `V`.
+	// The prefix is always case sensitive as SI prefixes differ by case (`m` is milli where
+	// `M` is mega), so `3.3 MV` is still parsed as megavolts rather than millivolts. Parse reports
+	// units registered with RegisterKnownUnit in their registered spelling, ie. `V` for `3.3 mv`.
+	CaseInsensitive bool
+	// RequireKnown rejects units detected by Parse that have not been registered with
+	// RegisterKnownUnit, returning ErrUnknownUnit
+	RequireKnown bool
+	// OnUnknownPrefix selects the handling of unrecognised prefixes, ie. a future prefix
+	// unknown to this package. Ignored prefixes are reported by UnmarshalDetailed.
+	OnUnknownPrefix PrefixPolicy
+	// GroupSep is the thousands grouping separator stripped from the integer digits prior to parsing,
+	// ie. ',' for `1,234.56 V`, which must separate groups of three digits. No grouping is accepted if zero.
+	GroupSep rune
+	// DecimalSep is the decimal separator, ie. ',' for `1.234,56 V` with a GroupSep of '.',
+	// defaults to '.' if zero. The separators must differ.
+	DecimalSep rune
+}
+
+// normalise strips grouping separators from the integer digits and replaces the decimal separator
+// with '.', returning the text unchanged where no separators are configured. Grouping separators
+// must separate groups of three digits, ie. `1,234` but not `3,30`, `1,2,3` or `,5`.
+func (p Parser) normalise(text []byte) ([]byte, error) {
+	if p.GroupSep == 0 && (p.DecimalSep == 0 || p.DecimalSep == '.') {
+		return text, nil
+	}
+	if p.GroupSep == p.DecimalSep || (p.DecimalSep == 0 && p.GroupSep == '.') {
+		return nil, fmt.Errorf("Invalid separators: grouping %q must differ from decimal %q", p.GroupSep, p.DecimalSep)
+	}
+
+	str := strings.TrimSpace(string(text))
+	out := make([]byte, 0, len(str))
+	i := 0
+	if i < len(str) && (str[i] == '+' || str[i] == '-') {
+		out = append(out, str[i])
+		i++
+	}
+
+	// Strip grouping separators from the integer digits, counting the digits in each group
+	digits, grouped := 0, false
+	for i < len(str) {
+		r, size := utf8.DecodeRuneInString(str[i:])
+		if r >= '0' && r <= '9' {
+			out = append(out, str[i])
+			digits++
+			i++
+			continue
+		}
+
+		// Separators are only grouping where followed by a digit, so `12 V` keeps its space
+		if r != p.GroupSep || i+size >= len(str) || str[i+size] < '0' || str[i+size] > '9' {
+			break
+		}
+		if digits == 0 || digits > 3 || (grouped && digits != 3) {
+			return nil, fmt.Errorf("%w: Invalid digit grouping in '%s'", ErrMalformedValue, str)
+		}
+		digits, grouped = 0, true
+		i += size
+	}
+	if grouped && digits != 3 {
+		return nil, fmt.Errorf("%w: Invalid digit grouping in '%s'", ErrMalformedValue, str)
+	}
+
+	// Replace the decimal separator in the remainder
+	return append(out, bytes.Map(func(r rune) rune {
+		if r == p.DecimalSep {
+			return '.'
+		}
+		return r
+	}, []byte(str[i:]))...), nil
+}
+
+// Parse parses SI unit text detecting the unit symbol as with the package level Parse
+func (p Parser) Parse(text []byte) (value float64, prefix string, unit string, err error) {
+	normalised, err := p.normalise(text)
+	if err != nil {
+		return 0.0, "", "", err
+	}
+
+	_, unitString, ok := matchUnit(normalised)
+	if !ok {
+		return 0.0, "", "", fmt.Errorf("%w: Unit must be of the form 'Value PrefixUnit`, ie. '100.2 kV'%s", ErrMalformedValue, swappedHint(text))
+	}
+
+	// Known units take precedence over splitting a prefix, so `Pa` is not read as peta-`a`.
+	// Known units matched regardless of case are reported with their registered spelling.
+	if known, ok := knownUnit(unitString, p.CaseInsensitive); p.RequireKnown && ok {
+		prefix, unit = "", known
+	} else {
+		prefix, unit = splitPrefix(unitString)
+		known, ok := knownUnit(unit, p.CaseInsensitive)
+		if p.RequireKnown && !ok {
+			return 0.0, "", "", fmt.Errorf("%w: Unrecognised unit: '%s'", ErrUnknownUnit, unit)
+		}
+		if ok {
+			unit = known
+		}
+	}
+
+	value, err = p.Unmarshal(unit, text)
+	if err != nil {
+		return 0.0, "", "", err
+	}
+
+	return value, prefix, unit, nil
+}
+
+// Unmarshal parses SI unit text with the expected unit as with UnmarshalUnit
+func (p Parser) Unmarshal(unit string, text []byte) (float64, error) {
+	value, _, err := p.UnmarshalDetailed(unit, text)
+	return value, err
+}
+
+// UnmarshalDetailed parses SI unit text as with Unmarshal, additionally reporting whether an
+// unrecognised prefix was ignored under PolicyIgnore, in which case the value is in the base unit
+func (p Parser) UnmarshalDetailed(unit string, text []byte) (value float64, ignored bool, err error) {
+	valueString, order, factor, ignored, err := p.split(unit, text)
+	if err != nil {
+		return 0.0, false, err
+	}
+
+	// Parse floating point component
+	base, err := strconv.ParseFloat(valueString, 64)
+	if err != nil {
+		return 0.0, false, fmt.Errorf("%w: %v", ErrMalformedValue, err)
+	}
+
+	// Pseudo-units are scaled by a fixed factor
+	if factor != 0 {
+		return base / factor, ignored, nil
+	}
+
+	// Multiply by prefix order
+	value = base * math.Pow(10, float64(order))
+
+	return value, ignored, nil
+}
+
+// split matches SI unit text against the expected unit, returning the value text and the decimal order
+// of the prefix, raised to the power of the leading unit component (ie. 6 for `km^2`). For pseudo-units
+// the fixed factor is returned, which is otherwise zero. Unrecognised prefixes ignored under
+// PolicyIgnore are reported with an order of zero.
+func (p Parser) split(unit string, text []byte) (valueString string, order int, factor float64, ignored bool, err error) {
+	normalised, err := p.normalise(text)
+	if err != nil {
+		return "", 0, 0, false, err
+	}
+
+	// Scan to check for sane strings
+	valueString, unitString, ok := matchUnit(normalised)
+	if !ok {
+		return "", 0, 0, false, fmt.Errorf("%w: Unit must be of the form 'Value PrefixUnit`, ie. '100.2 k%s'%s", ErrMalformedValue, unit, swappedHint(text))
+	}
+
+	// Check suffix matches and strip to find the prefix
+	prefix, ok := p.trimUnit(unitString, unit)
+	if !ok {
+		return "", 0, 0, false, fmt.Errorf("%w: Unable to parse unit: '%s' expected suffix: '%s'", ErrUnitMismatch, unitString, unit)
+	}
+
+	// Pseudo-units are scaled by a fixed factor and do not accept prefixes
+	if factor, ok := pseudoUnits[unit]; ok {
+		if prefix != "" && p.OnUnknownPrefix != PolicyIgnore {
+			return "", 0, 0, false, fmt.Errorf("%w: Unexpected prefix: '%s' for unit: '%s'", ErrUnknownPrefix, prefix, unit)
+		}
+		return valueString, 0, factor, prefix != "", nil
+	}
+
+	// Calculate order from prefix
+	table := tableFor(unit)
+	order, ok = parseOrder(table, prefix)
+	if !ok && p.OnUnknownPrefix == PolicyIgnore {
+		return valueString, 0, 0, true, nil
+	}
+	if !ok {
+		return "", 0, 0, false, fmt.Errorf("%w: Unrecognised SI prefix: '%s' (options: %s)", ErrUnknownPrefix, prefix, strings.Join(table.prefixes, ", "))
+	}
+
+	return valueString, order * leadingPower(unit), 0, false, nil
+}
+
+// parseOrder returns the order of a parsed prefix, accepting the parse only prefixes for SI units
+func parseOrder(table *prefixTable, prefix string) (int, bool) {
+	order, ok := table.order(prefix)
+	if !ok && table == siPrefixes() {
+		order, ok = parsePrefixes[prefix]
+	}
+	return order, ok
+}
+
+// trimUnit checks the unit string ends with the expected unit, returning the remaining prefix
+func (p Parser) trimUnit(unitString, unit string) (string, bool) {
+	if len(unitString) < len(unit) {
+		return "", false
+	}
+
+	prefix, suffix := unitString[:len(unitString)-len(unit)], unitString[len(unitString)-len(unit):]
+	if suffix == unit || (p.CaseInsensitive && strings.EqualFold(suffix, unit)) {
+		return prefix, true
+	}
+
+	return "", false
+}
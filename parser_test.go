@@ -0,0 +1,168 @@
+package units
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestParserCaseInsensitive(t *testing.T) {
+	if _, err := UnmarshalUnit("V", []byte("3.3 mv")); !errors.Is(err, ErrUnitMismatch) {
+		t.Error(err)
+	}
+	p := Parser{CaseInsensitive: true}
+	if v, err := p.Unmarshal("V", []byte("3.3 mv")); err != nil || v < 0.00329 || v > 0.00331 {
+		t.Error(v, err)
+	}
+	if v, err := p.Unmarshal("Hz", []byte("12 Khz")); err != nil || v != 12000 {
+		t.Error(v, err)
+	}
+	if v, err := p.Unmarshal("V", []byte("3.3 MV")); err != nil || v != 3.3e6 {
+		t.Error(v, err)
+	}
+	if _, err := p.Unmarshal("Hz", []byte("12 z")); !errors.Is(err, ErrUnitMismatch) {
+		t.Error(err)
+	}
+	for _, s := range []string{"Hz", "V", "B", "b"} {
+		RegisterKnownUnit(s)
+	}
+	for _, c := range []struct {
+		s, prefix, unit string
+		v               float64
+	}{{"3.3 mv", "m", "V", 0.0033}, {"12 khz", "k", "Hz", 12000}, {"2 kB", "k", "B", 2000}} {
+		for _, p := range []Parser{{CaseInsensitive: true}, {CaseInsensitive: true, RequireKnown: true}} {
+			v, prefix, unit, err := p.Parse([]byte(c.s))
+			if err != nil || math.Abs(v-c.v) > 1e-12 || prefix != c.prefix || unit != c.unit {
+				t.Error(c, p, v, prefix, unit, err)
+			}
+		}
+	}
+	if _, _, _, err := (Parser{RequireKnown: true}).Parse([]byte("12 khz")); !errors.Is(err, ErrUnknownUnit) {
+		t.Error(err)
+	}
+}
+
+func TestParserRequireKnown(t *testing.T) {
+	for _, s := range []string{"Hz", "V", "A", "W", "Ohm", "F", "Pa"} {
+		RegisterKnownUnit(s)
+	}
+	p := Parser{RequireKnown: true}
+	v, prefix, unit, err := p.Parse([]byte("3.3 mV"))
+	if err != nil || v != 0.0033 || prefix != "m" || unit != "V" {
+		t.Fatal(v, prefix, unit, err)
+	}
+	v, prefix, unit, err = p.Parse([]byte("2 Pa"))
+	if err != nil || v != 2 || prefix != "" || unit != "Pa" {
+		t.Fatal(v, prefix, unit, err)
+	}
+	v, _, unit, err = p.Parse([]byte("4.7 kOhm"))
+	if err != nil || v != 4700 || unit != "Ohm" {
+		t.Fatal(v, unit, err)
+	}
+	if _, _, _, err := p.Parse([]byte("3 Xyz")); !errors.Is(err, ErrUnknownUnit) {
+		t.Fatal(err)
+	}
+	if _, _, _, err := Parse([]byte("3 Xyz")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSwappedHint(t *testing.T) {
+	_, err := UnmarshalUnit("Hz", []byte("Hz 12000"))
+	if !errors.Is(err, ErrMalformedValue) || !strings.Contains(err.Error(), "did you mean '12000 Hz'?") {
+		t.Fatal(err)
+	}
+	_, err = UnmarshalUnit("V", []byte(" mV-3.3 "))
+	if !strings.Contains(err.Error(), "did you mean '-3.3 mV'?") {
+		t.Fatal(err)
+	}
+	_, _, _, err = Parse([]byte("KHz 1.5"))
+	if !strings.Contains(err.Error(), "did you mean '1.5 KHz'?") {
+		t.Fatal(err)
+	}
+	_, err = UnmarshalUnit("Hz", []byte("garbage"))
+	if strings.Contains(err.Error(), "did you mean") {
+		t.Fatal(err)
+	}
+}
+
+func TestParserUnknownPrefixPolicy(t *testing.T) {
+	if _, err := (Parser{}).Unmarshal("Hz", []byte("3 XHz")); !errors.Is(err, ErrUnknownPrefix) {
+		t.Fatal(err)
+	}
+	p := Parser{OnUnknownPrefix: PolicyIgnore}
+	v, ignored, err := p.UnmarshalDetailed("Hz", []byte("3 XHz"))
+	if err != nil || !ignored || v != 3 {
+		t.Fatal(v, ignored, err)
+	}
+	v, ignored, err = p.UnmarshalDetailed("Hz", []byte("3 kHz"))
+	if err != nil || ignored || v != 3000 {
+		t.Fatal(v, ignored, err)
+	}
+	if v, err := p.Unmarshal("Hz", []byte("3 XyHz")); err != nil || v != 3 {
+		t.Fatal(v, err)
+	}
+	if _, _, err := p.UnmarshalDetailed("Hz", []byte("3 V")); !errors.Is(err, ErrUnitMismatch) {
+		t.Fatal(err)
+	}
+}
+
+func TestParserSeparators(t *testing.T) {
+	us := Parser{GroupSep: ','}
+	if v, err := us.Unmarshal("V", []byte("1,234.56 V")); err != nil || v != 1234.56 {
+		t.Error(v, err)
+	}
+	if v, err := us.Unmarshal("V", []byte("1,234,567 mV")); err != nil || math.Abs(v-1234.567) > 1e-9 {
+		t.Error(v, err)
+	}
+	eu := Parser{GroupSep: '.', DecimalSep: ','}
+	if v, err := eu.Unmarshal("V", []byte("1.234,56 kV")); err != nil || v != 1234560 {
+		t.Error(v, err)
+	}
+	if v, p, u, err := eu.Parse([]byte("1.234,5 mV")); err != nil || math.Abs(v-1.2345) > 1e-12 || p != "m" || u != "V" {
+		t.Error(v, p, u, err)
+	}
+	fr := Parser{GroupSep: ' ', DecimalSep: ','}
+	if v, err := fr.Unmarshal("V", []byte("1 234,5 V")); err != nil || v != 1234.5 {
+		t.Error(v, err)
+	}
+	if _, err := UnmarshalUnit("V", []byte("1,234.56 V")); err == nil {
+		t.Error("expected default to reject commas")
+	}
+	if _, err := (Parser{GroupSep: ',', DecimalSep: ','}).Unmarshal("V", []byte("1 V")); err == nil {
+		t.Error("expected separator conflict")
+	}
+	if _, err := (Parser{GroupSep: '.'}).Unmarshal("V", []byte("1 V")); err == nil {
+		t.Error("expected separator conflict")
+	}
+	if v, err := (Parser{DecimalSep: ','}).Unmarshal("V", []byte("3,3 mV")); err != nil || v != 0.0033 {
+		t.Error(v, err)
+	}
+	for _, text := range []string{"12 V", "+1,000 V", "-12,345,678V", "999 kV"} {
+		if _, err := us.Unmarshal("V", []byte(text)); err != nil {
+			t.Errorf("%q: %v", text, err)
+		}
+	}
+}
+
+func TestParserSeparatorPlacement(t *testing.T) {
+	cases := []struct {
+		p    Parser
+		text string
+	}{
+		{Parser{GroupSep: ','}, "3,30 V"},
+		{Parser{GroupSep: ','}, "1,2,3 V"},
+		{Parser{GroupSep: ','}, ",5 V"},
+		{Parser{GroupSep: ','}, "1234,567 V"},
+		{Parser{GroupSep: ','}, "1,234,56 V"},
+		{Parser{GroupSep: ','}, "1,234.5,6 V"},
+		{Parser{GroupSep: '.', DecimalSep: ','}, "1.23,4 V"},
+		{Parser{GroupSep: ' ', DecimalSep: ','}, "1 5 V"},
+	}
+	for _, c := range cases {
+		if v, err := c.p.Unmarshal("V", []byte(c.text)); !errors.Is(err, ErrMalformedValue) {
+			t.Errorf("%q: got %v, %v", c.text, v, err)
+		}
+	}
+}
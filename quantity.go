@@ -0,0 +1,145 @@
+package units
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Dimension is a physical dimension expressed as exponents of the SI base dimensions
+type Dimension struct {
+	Length      int
+	Mass        int
+	Time        int
+	Current     int
+	Temperature int
+	Amount      int
+	Luminosity  int
+}
+
+// Common dimensions
+var (
+	Dimensionless = Dimension{}
+	Length        = Dimension{Length: 1}
+	Mass          = Dimension{Mass: 1}
+	Time          = Dimension{Time: 1}
+	Current       = Dimension{Current: 1}
+	Temperature   = Dimension{Temperature: 1}
+	Amount        = Dimension{Amount: 1}
+	Luminosity    = Dimension{Luminosity: 1}
+	Area          = Dimension{Length: 2}
+	Volume        = Dimension{Length: 3}
+	Velocity      = Dimension{Length: 1, Time: -1}
+	Frequency     = Dimension{Time: -1}
+)
+
+// Mul returns the dimension of the product of two quantities
+func (d Dimension) Mul(other Dimension) Dimension {
+	return Dimension{
+		Length:      d.Length + other.Length,
+		Mass:        d.Mass + other.Mass,
+		Time:        d.Time + other.Time,
+		Current:     d.Current + other.Current,
+		Temperature: d.Temperature + other.Temperature,
+		Amount:      d.Amount + other.Amount,
+		Luminosity:  d.Luminosity + other.Luminosity,
+	}
+}
+
+// Div returns the dimension of the quotient of two quantities
+func (d Dimension) Div(other Dimension) Dimension {
+	return d.Mul(Dimension{
+		Length:      -other.Length,
+		Mass:        -other.Mass,
+		Time:        -other.Time,
+		Current:     -other.Current,
+		Temperature: -other.Temperature,
+		Amount:      -other.Amount,
+		Luminosity:  -other.Luminosity,
+	})
+}
+
+// String formats a dimension using the base dimension symbols, ie. `L·T^-1` for velocity
+func (d Dimension) String() string {
+	exponents := []struct {
+		symbol   string
+		exponent int
+	}{
+		{"L", d.Length}, {"M", d.Mass}, {"T", d.Time}, {"I", d.Current},
+		{"Θ", d.Temperature}, {"N", d.Amount}, {"J", d.Luminosity},
+	}
+
+	parts := make([]string, 0, len(exponents))
+	for _, e := range exponents {
+		switch e.exponent {
+		case 0:
+		case 1:
+			parts = append(parts, e.symbol)
+		default:
+			parts = append(parts, fmt.Sprintf("%s^%d", e.symbol, e.exponent))
+		}
+	}
+	if len(parts) == 0 {
+		return "1"
+	}
+
+	return strings.Join(parts, "·")
+}
+
+// Quantity is a unit value tagged with its physical dimension, preventing arithmetic
+// between incompatible dimensions
+type Quantity struct {
+	Symbol    string
+	Value     float64
+	Dimension Dimension
+}
+
+// NewQuantity creates a quantity with the provided symbol, base value, and dimension
+func NewQuantity(symbol string, value float64, dimension Dimension) Quantity {
+	return Quantity{Symbol: symbol, Value: value, Dimension: dimension}
+}
+
+// Unit returns the symbol and value of the quantity as a Unit
+func (q Quantity) Unit() Unit {
+	return Unit{Symbol: q.Symbol, Value: q.Value}
+}
+
+// String formats a quantity as with Unit.String
+func (q Quantity) String() string {
+	return q.Unit().String()
+}
+
+// Add returns the sum of two quantities, returning an error if the dimensions differ.
+// The result takes the symbol of the receiver.
+func (q Quantity) Add(other Quantity) (Quantity, error) {
+	if q.Dimension != other.Dimension {
+		return Quantity{}, fmt.Errorf("%w: Unable to add '%s' and '%s'", ErrDimensionMismatch, q.Dimension, other.Dimension)
+	}
+	return Quantity{Symbol: q.Symbol, Value: q.Value + other.Value, Dimension: q.Dimension}, nil
+}
+
+// Sub returns the difference of two quantities, returning an error if the dimensions differ.
+// The result takes the symbol of the receiver.
+func (q Quantity) Sub(other Quantity) (Quantity, error) {
+	if q.Dimension != other.Dimension {
+		return Quantity{}, fmt.Errorf("%w: Unable to subtract '%s' from '%s'", ErrDimensionMismatch, other.Dimension, q.Dimension)
+	}
+	return Quantity{Symbol: q.Symbol, Value: q.Value - other.Value, Dimension: q.Dimension}, nil
+}
+
+// Mul returns the product of two quantities, combining symbols with `·` and dimensions
+func (q Quantity) Mul(other Quantity) Quantity {
+	return Quantity{
+		Symbol:    q.Symbol + "·" + other.Symbol,
+		Value:     q.Value * other.Value,
+		Dimension: q.Dimension.Mul(other.Dimension),
+	}
+}
+
+// Div returns the quotient of two quantities, combining symbols with `/` and dimensions
+func (q Quantity) Div(other Quantity) Quantity {
+	return Quantity{
+		Symbol:    q.Symbol + "/" + other.Symbol,
+		Value:     q.Value / other.Value,
+		Dimension: q.Dimension.Div(other.Dimension),
+	}
+}
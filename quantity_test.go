@@ -0,0 +1,29 @@
+package units
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestQuantity(t *testing.T) {
+	a, b := NewQuantity("m", 3, Length), NewQuantity("m", 2, Length)
+	s := NewQuantity("s", 2, Time)
+	if sum, err := a.Add(b); err != nil || sum.Value != 5 {
+		t.Error(sum, err)
+	}
+	if _, err := a.Add(s); !errors.Is(err, ErrDimensionMismatch) {
+		t.Error(err)
+	}
+	if _, err := a.Sub(s); !errors.Is(err, ErrDimensionMismatch) {
+		t.Error(err)
+	}
+	if ar := a.Mul(b); ar.Dimension != Area || ar.Value != 6 || ar.Symbol != "m·m" {
+		t.Error(ar)
+	}
+	if v := a.Div(s); v.Dimension != Velocity || v.Value != 1.5 || v.Symbol != "m/s" || v.String() != "1.50 m/s" {
+		t.Error(v, v.String())
+	}
+	if Velocity.String() != "L·T^-1" || Dimensionless.String() != "1" {
+		t.Error(Velocity.String())
+	}
+}
@@ -0,0 +1,35 @@
+package units
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// rangeRegex matches ranges of the form `[lo]-[hi] [prefix][unit]` or `[lo] .. [hi] [prefix][unit]`
+var rangeRegex = regexp.MustCompile(`^(` + numberPattern + `)[ ]*(?:\.\.|-)[ ]*(` + numberPattern + `)[ ]{0,1}(` + unitPattern + `)$`)
+
+// ParseRange parses a range of values sharing a unit, ie. `1.2-3.4 V` or `1.2 .. 3.4 mV`,
+// with the prefix applied to both bounds. The first `-` following the lower bound is the range
+// separator, so a negative upper bound follows it directly, ie. `-5--2 V` is -5 to -2 volts.
+func ParseRange(unit string, text []byte) (lo, hi float64, err error) {
+	matches := rangeRegex.FindStringSubmatch(strings.TrimSpace(string(text)))
+	if matches == nil {
+		return 0.0, 0.0, fmt.Errorf("%w: Range must be of the form 'Lo-Hi PrefixUnit` or 'Lo .. Hi PrefixUnit`, ie. '1.2-3.4 k%s'", ErrMalformedValue, unit)
+	}
+
+	lo, err = UnmarshalUnit(unit, []byte(matches[1]+" "+matches[3]))
+	if err != nil {
+		return 0.0, 0.0, err
+	}
+	hi, err = UnmarshalUnit(unit, []byte(matches[2]+" "+matches[3]))
+	if err != nil {
+		return 0.0, 0.0, err
+	}
+
+	if lo > hi {
+		return 0.0, 0.0, fmt.Errorf("%w: Range lower bound %g exceeds upper bound %g", ErrMalformedValue, lo, hi)
+	}
+
+	return lo, hi, nil
+}
@@ -0,0 +1,26 @@
+package units
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseRange(t *testing.T) {
+	for _, c := range []struct {
+		s      string
+		lo, hi float64
+	}{{"1.2-3.4 V", 1.2, 3.4}, {"1.2 .. 3.4 V", 1.2, 3.4}, {"-5--2 V", -5, -2}, {"-5-2 V", -5, 2}, {"-5 .. -2 V", -5, -2}, {"1-2 kV", 1000, 2000}, {"1e-3-2e-3 V", 0.001, 0.002}, {"1..2V", 1, 2}} {
+		lo, hi, err := ParseRange("V", []byte(c.s))
+		if err != nil || lo != c.lo || hi != c.hi {
+			t.Error(c, lo, hi, err)
+		}
+	}
+	for _, s := range []string{"1.2 V", "1.2-- V", "3-1 V", "1-2-3 V", "a-b V"} {
+		if _, _, err := ParseRange("V", []byte(s)); !errors.Is(err, ErrMalformedValue) {
+			t.Error(s, err)
+		}
+	}
+	if _, _, err := ParseRange("V", []byte("1-2 A")); !errors.Is(err, ErrUnitMismatch) {
+		t.Error(err)
+	}
+}
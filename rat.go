@@ -0,0 +1,38 @@
+package units
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// UnmarshalUnitRat is a helper for common (SI) unit deserialisation/unmarshalling as with
+// UnmarshalUnit, computing the value as an exact rational without floating point error,
+// ie. `3.3 mV` is exactly 33/10000
+func UnmarshalUnitRat(unit string, text []byte) (*big.Rat, error) {
+	valueString, order, factor, _, err := Parser{}.split(unit, text)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse the exact decimal component
+	value, ok := new(big.Rat).SetString(valueString)
+	if !ok {
+		return nil, fmt.Errorf("%w: Unable to parse value: '%s'", ErrMalformedValue, valueString)
+	}
+
+	// Pseudo-units are scaled by a fixed factor
+	if factor != 0 {
+		return value.Quo(value, new(big.Rat).SetFloat64(factor)), nil
+	}
+
+	// Scale by the prefix order
+	exponent := int64(order)
+	if exponent < 0 {
+		exponent = -exponent
+	}
+	scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(exponent), nil))
+	if order < 0 {
+		return value.Quo(value, scale), nil
+	}
+	return value.Mul(value, scale), nil
+}
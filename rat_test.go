@@ -0,0 +1,32 @@
+package units
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestUnmarshalUnitRat(t *testing.T) {
+	cases := map[string]string{
+		"3.3 mV": "33/10000", "12 kV": "12000/1", "+1.5e3 MV": "1500000000/1", "-2 uV": "-1/500000", "5 cV": "1/20",
+	}
+	for in, want := range cases {
+		r, err := UnmarshalUnitRat("V", []byte(in))
+		if err != nil || r.String() != want {
+			t.Errorf("%q: %v %v", in, r, err)
+		}
+	}
+	r, _ := UnmarshalUnitRat("V", []byte("3.3 mV"))
+	if r.Cmp(big.NewRat(33, 10000)) != 0 {
+		t.Error(r)
+	}
+	if f, _ := UnmarshalUnit("V", []byte("3.3 mV")); new(big.Rat).SetFloat64(f).Cmp(r) == 0 {
+		t.Error("float path unexpectedly exact")
+	}
+	if r, err := UnmarshalUnitRat("%", []byte("12.5 %")); err != nil || r.String() != "1/8" {
+		t.Error(r, err)
+	}
+	if _, err := UnmarshalUnitRat("V", []byte("3.3 XV")); !errors.Is(err, ErrUnknownPrefix) {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,44 @@
+package units
+
+import (
+	"bufio"
+	"io"
+)
+
+// UnitReader parses a stream of whitespace separated unit values, ie. `3.3 mV 4.7mV 1.2 V`,
+// without loading the entire input
+type UnitReader struct {
+	scanner *bufio.Scanner
+	unit    string
+}
+
+// NewReader creates a UnitReader parsing values of the provided unit from r
+func NewReader(r io.Reader, unit string) *UnitReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Split(bufio.ScanWords)
+	return &UnitReader{scanner: scanner, unit: unit}
+}
+
+// Next returns the next base value from the stream, or io.EOF once the input is exhausted.
+// Values may be fused with or separated from the unit as with Unit.Scan.
+func (r *UnitReader) Next() (float64, error) {
+	if !r.scanner.Scan() {
+		return 0.0, r.eof()
+	}
+	text := append([]byte(nil), r.scanner.Bytes()...)
+
+	// Otherwise the unit follows as a separate token
+	if _, _, ok := matchUnit(text); !ok && r.scanner.Scan() {
+		text = append(append(text, ' '), r.scanner.Bytes()...)
+	}
+
+	return UnmarshalUnit(r.unit, text)
+}
+
+// eof returns the underlying read error, or io.EOF if the input ended cleanly
+func (r *UnitReader) eof() error {
+	if err := r.scanner.Err(); err != nil {
+		return err
+	}
+	return io.EOF
+}
@@ -0,0 +1,34 @@
+package units
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestUnitReader(t *testing.T) {
+	r := NewReader(strings.NewReader("  3.3 mV\n4.7mV\t\t1.2 V   \n\n"), "V")
+	want := []float64{3.3e-3, 4.7e-3, 1.2}
+	for i, w := range want {
+		v, err := r.Next()
+		if err != nil || !(Unit{"V", v}).EqualTol(Unit{"V", w}, 1e-12) {
+			t.Fatalf("%d: %v %v", i, v, err)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := r.Next(); err != io.EOF {
+			t.Fatal(err)
+		}
+	}
+	r = NewReader(strings.NewReader("3.3 mA 5"), "V")
+	if _, err := r.Next(); !errors.Is(err, ErrUnitMismatch) {
+		t.Fatal(err)
+	}
+	if _, err := r.Next(); !errors.Is(err, ErrMalformedValue) {
+		t.Fatal(err)
+	}
+	if _, err := NewReader(strings.NewReader(""), "V").Next(); err != io.EOF {
+		t.Fatal(err)
+	}
+}
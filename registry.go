@@ -0,0 +1,128 @@
+package units
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// prefixTable is a set of prefixes and their associated orders
+type prefixTable struct {
+	prefixes  []string
+	prefixMap map[string]int
+	orderMap  map[int]string
+	minOrder  int
+	maxOrder  int
+}
+
+func newPrefixTable(prefixes []string, orders []int) *prefixTable {
+	t := prefixTable{
+		prefixes:  append([]string(nil), prefixes...),
+		prefixMap: make(map[string]int),
+		orderMap:  make(map[int]string),
+	}
+	for i := range prefixes {
+		t.prefixMap[prefixes[i]] = orders[i]
+		t.orderMap[orders[i]] = prefixes[i]
+		if i == 0 || orders[i] < t.minOrder {
+			t.minOrder = orders[i]
+		}
+		if i == 0 || orders[i] > t.maxOrder {
+			t.maxOrder = orders[i]
+		}
+	}
+	return &t
+}
+
+// order returns the order of a prefix, accepting aliases of the canonical SI prefixes
+// (ie. `µ` or `K`) where the table does not define them
+func (t *prefixTable) order(prefix string) (int, bool) {
+	if order, ok := t.prefixMap[prefix]; ok {
+		return order, true
+	}
+	order, ok := t.prefixMap[canonicalPrefix(prefix)]
+	return order, ok
+}
+
+var registry = struct {
+	sync.RWMutex
+	tables map[string]*prefixTable
+	known  map[string]bool
+	binary map[string]bool
+}{tables: make(map[string]*prefixTable), known: make(map[string]bool), binary: map[string]bool{"B": true, "b": true}}
+
+// RegisterUnit registers a custom prefix table for a unit symbol, to be used in place of the
+// SI Prefixes and Orders when marshalling and unmarshalling that unit. Orders must be multiples
+// of the Formatter Step (3 by default) to be selected when marshalling. Registering an existing symbol replaces its table.
+// RegisterUnit panics if the prefixes and orders differ in length.
+func RegisterUnit(symbol string, prefixes []string, orders []int64) {
+	if len(prefixes) != len(orders) {
+		panic(fmt.Sprintf("units: RegisterUnit %s: %d prefixes with %d orders", symbol, len(prefixes), len(orders)))
+	}
+
+	o := make([]int, len(orders))
+	for i := range orders {
+		o[i] = int(orders[i])
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+	registry.tables[symbol] = newPrefixTable(prefixes, o)
+}
+
+// RegisterKnownUnit registers a unit symbol as known, for validation of detected units
+// when parsing with Parser.RequireKnown, ie. `Hz`, `V`, `A`, `W`, `Ohm`, `F`
+func RegisterKnownUnit(symbol string) {
+	registry.Lock()
+	defer registry.Unlock()
+	registry.known[symbol] = true
+}
+
+// knownUnit returns the spelling of a unit symbol registered with RegisterKnownUnit, matching
+// regardless of case where fold is set. Symbols matching several known units by case (ie. `B` and `b`)
+// are only matched exactly.
+func knownUnit(symbol string, fold bool) (string, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	if registry.known[symbol] {
+		return symbol, true
+	}
+	if !fold {
+		return "", false
+	}
+	match := ""
+	for known := range registry.known {
+		if strings.EqualFold(known, symbol) {
+			if match != "" {
+				return "", false
+			}
+			match = known
+		}
+	}
+	return match, match != ""
+}
+
+// RegisterBinaryUnit registers a unit symbol as a data size, to be formatted with binary (IEC)
+// prefixes by MarshalAuto. Bytes (`B`) and bits (`b`) are registered by default.
+func RegisterBinaryUnit(symbol string) {
+	registry.Lock()
+	defer registry.Unlock()
+	registry.binary[symbol] = true
+}
+
+// isBinaryUnit reports whether a unit symbol has been registered with RegisterBinaryUnit
+func isBinaryUnit(symbol string) bool {
+	registry.RLock()
+	defer registry.RUnlock()
+	return registry.binary[symbol]
+}
+
+// tableFor returns the registered prefix table for a unit symbol, falling back to SI
+func tableFor(unit string) *prefixTable {
+	registry.RLock()
+	defer registry.RUnlock()
+	if t, ok := registry.tables[unit]; ok {
+		return t
+	}
+	return siPrefixes()
+}
@@ -0,0 +1,30 @@
+package units
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRegisterUnit(t *testing.T) {
+	RegisterUnit("tst", []string{"", "k"}, []int64{0, 3})
+	RegisterUnit("deg", []string{"", "x"}, []int64{0, 3})
+	RegisterUnit("deg", []string{"m", ""}, []int64{-3, 0})
+	if b, err := MarshalUnit("deg", 0.0045); err != nil || string(b) != "4.50 mdeg" {
+		t.Error(string(b), err)
+	}
+	if _, err := MarshalUnit("deg", 4500); !errors.Is(err, ErrOutOfRange) {
+		t.Error(err)
+	}
+	if v, err := UnmarshalUnit("deg", []byte("4.5 mdeg")); err != nil || v < 0.00449999 || v > 0.00450001 {
+		t.Error(v, err)
+	}
+	if _, err := UnmarshalUnit("deg", []byte("4.5 xdeg")); !errors.Is(err, ErrUnknownPrefix) {
+		t.Error(err)
+	}
+	if v, err := UnmarshalUnit("tst", []byte("1 ktst")); err != nil || v != 1000 {
+		t.Error(v, err)
+	}
+	if b, _ := MarshalUnit("V", 4500); string(b) != "4.50 kV" {
+		t.Error(string(b))
+	}
+}
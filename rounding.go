@@ -0,0 +1,99 @@
+package units
+
+import (
+	"bytes"
+	"strconv"
+)
+
+// RoundingMode selects how values are rounded to the formatter precision
+type RoundingMode int
+
+const (
+	// RoundHalfEven rounds the shortest decimal representation of the value with ties to even,
+	// ie. 2.345 -> 2.34 and 2.355 -> 2.36
+	RoundHalfEven RoundingMode = iota
+	// RoundHalfUp rounds the shortest decimal representation of the value with ties away
+	// from zero, ie. 2.345 -> 2.35
+	RoundHalfUp
+	// RoundTruncate truncates the shortest decimal representation of the value toward zero,
+	// ie. 2.349 -> 2.34
+	RoundTruncate
+)
+
+// appendRound appends a non-negative value rounded to the provided number of decimal places to dst
+func (r RoundingMode) appendRound(dst []byte, value float64, precision int) []byte {
+	// Split the shortest decimal representation into integer and fractional digits
+	start := len(dst)
+	dst = strconv.AppendFloat(dst, value, 'f', -1, 64)
+	point := bytes.IndexByte(dst[start:], '.')
+	if point < 0 {
+		if precision == 0 {
+			return dst
+		}
+		point = len(dst) - start
+		dst = append(dst, '.')
+	}
+	point += start
+	fraction := len(dst) - point - 1
+
+	if fraction <= precision {
+		for ; fraction < precision; fraction++ {
+			dst = append(dst, '0')
+		}
+		return dst
+	}
+
+	// Drop the excess digits, rounding up on the first dropped digit if required
+	end := point + 1 + precision
+	if precision == 0 {
+		end = point
+	}
+	var up bool
+	switch dropped := dst[point+1+precision:]; r {
+	case RoundHalfUp:
+		up = dropped[0] >= '5'
+	case RoundTruncate:
+		up = false
+	default:
+		// Ties (a single dropped 5, as the shortest representation has no trailing zeros)
+		// round to an even last digit
+		up = dropped[0] > '5' || (dropped[0] == '5' && (len(dropped) > 1 || lastDigit(dst[start:end])%2 == 1))
+	}
+	dst = dst[:end]
+	if up {
+		dst = increment(dst, start)
+	}
+
+	return dst
+}
+
+// lastDigit returns the value of the last decimal digit in digits, skipping any decimal point
+func lastDigit(digits []byte) int {
+	for i := len(digits) - 1; i >= 0; i-- {
+		if digits[i] != '.' {
+			return int(digits[i] - '0')
+		}
+	}
+	return 0
+}
+
+// increment adds one unit in the last place to the decimal digits in dst[start:]
+func increment(dst []byte, start int) []byte {
+	i := len(dst) - 1
+	for ; i >= start; i-- {
+		if dst[i] == '.' {
+			continue
+		}
+		if dst[i] != '9' {
+			dst[i]++
+			return dst
+		}
+		dst[i] = '0'
+	}
+
+	// Carry out of the most significant digit
+	dst = append(dst, 0)
+	copy(dst[start+1:], dst[start:])
+	dst[start] = '1'
+	return dst
+}
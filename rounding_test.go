@@ -0,0 +1,31 @@
+package units
+
+import "testing"
+
+func TestRoundingModes(t *testing.T) {
+	for _, c := range []struct {
+		m    RoundingMode
+		v    float64
+		p    int
+		want string
+	}{
+		{RoundHalfEven, 2.345, 2, "2.34"}, {RoundHalfUp, 2.345, 2, "2.35"}, {RoundTruncate, 2.345, 2, "2.34"},
+		{RoundHalfEven, 2.355, 2, "2.36"}, {RoundHalfEven, 2.3451, 2, "2.35"}, {RoundHalfEven, 1.005, 2, "1.00"},
+		{RoundHalfEven, 9.5, 0, "10"}, {RoundHalfEven, 8.5, 0, "8"}, {RoundHalfEven, 999.995, 2, "1000.00"},
+		{RoundHalfEven, 0.125, 2, "0.12"}, {RoundHalfUp, 0.125, 2, "0.13"}, {RoundTruncate, 0.129, 2, "0.12"},
+		{RoundHalfUp, 999.995, 2, "1000.00"}, {RoundHalfUp, 9.5, 0, "10"}, {RoundHalfUp, 200, 0, "200"}, {RoundHalfEven, 200, 0, "200"}, {RoundTruncate, 9.99, 0, "9"},
+		{RoundHalfUp, 1.005, 2, "1.01"}, {RoundHalfUp, 3, 3, "3.000"}, {RoundHalfUp, 1e-7, 2, "0.00"},
+	} {
+		if s := string(c.m.appendRound(nil, c.v, c.p)); s != c.want {
+			t.Error(c, s)
+		}
+	}
+	f := Formatter{Precision: 2, Rounding: RoundHalfUp}
+	if s := f.Format("V", -999.995); s != "-1.00 kV" {
+		t.Error(s)
+	}
+	f.Rounding = RoundTruncate
+	if s := f.Format("V", -2.349); s != "-2.34 V" {
+		t.Error(s)
+	}
+}
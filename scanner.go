@@ -0,0 +1,134 @@
+package units
+
+import (
+	"strings"
+)
+
+// scanned is the result of scanning unit text, with the value split into its components
+type scanned struct {
+	// value is the complete value text, ie. `-1.2e3`
+	value string
+	// sign is the optional leading sign of the value
+	sign string
+	// integer and fraction are the digits either side of the decimal point
+	integer  string
+	fraction string
+	// exponent is the optionally signed decimal exponent
+	exponent string
+	// unit is the prefixed (optionally compound) unit or pseudo-unit
+	unit string
+}
+
+// scanUnit scans unit strings of the form `[sign][integer].[fraction][e[exponent]] [prefix][unit]`
+// ie. `10.2 dBmV` or `1.2e3 Hz`, where the unit may be compound with `/` or `·` separated components
+// and `^` powers, ie. `9.8 m/s^2` or `3 N·m`, or one of the pseudo-units `%` and `‰`.
+// The exponent is only consumed where digits follow, so `2eV` is 2 eV while `1e3Hz` is 1000 Hz.
+// The sign and exponent sign are independent, ie. `-1.5e-3 V` is -1.5 mV and `+1.5e+3 V` is 1.5 kV.
+func scanUnit(str string) (scanned, bool) {
+	var s scanned
+	i := 0
+
+	// Sign and integer digits
+	if i < len(str) && (str[i] == '+' || str[i] == '-') {
+		s.sign = str[:1]
+		i++
+	}
+	end := scanDigits(str, i)
+	if end == i {
+		return scanned{}, false
+	}
+	s.integer, i = str[i:end], end
+
+	// Fractional digits, which are required following a decimal point
+	if i < len(str) && str[i] == '.' {
+		end = scanDigits(str, i+1)
+		if end == i+1 {
+			return scanned{}, false
+		}
+		s.fraction, i = str[i+1:end], end
+	}
+
+	// Exponent, where followed by (optionally signed) digits
+	if i < len(str) && (str[i] == 'e' || str[i] == 'E') {
+		start := i + 1
+		if start < len(str) && (str[start] == '+' || str[start] == '-') {
+			start++
+		}
+		if end = scanDigits(str, start); end > start {
+			s.exponent, i = str[i+1:end], end
+		}
+	}
+	s.value = str[:i]
+
+	// Optional separating space and unit
+	if i < len(str) && str[i] == ' ' {
+		i++
+	}
+	s.unit = str[i:]
+	if !validUnit(s.unit) {
+		return scanned{}, false
+	}
+
+	return s, true
+}
+
+// validUnit checks a unit is a pseudo-unit or components of ASCII letters with optional `^` powers,
+// separated by `/` or `·` and with an optional leading micro sign, ie. `µm/s^2`
+func validUnit(unit string) bool {
+	if unit == "%" || unit == "‰" {
+		return true
+	}
+
+	for _, micro := range []string{"µ", "μ"} {
+		if strings.HasPrefix(unit, micro) {
+			unit = unit[len(micro):]
+			break
+		}
+	}
+
+	for {
+		// Component letters and optional power
+		end := scanLetters(unit, 0)
+		if end == 0 {
+			return false
+		}
+		if end < len(unit) && unit[end] == '^' {
+			start := end + 1
+			if start < len(unit) && unit[start] == '-' {
+				start++
+			}
+			if end = scanDigits(unit, start); end == start {
+				return false
+			}
+		}
+		unit = unit[end:]
+
+		// Separator for the next component
+		switch {
+		case unit == "":
+			return true
+		case strings.HasPrefix(unit, "/"):
+			unit = unit[1:]
+		case strings.HasPrefix(unit, "·"):
+			unit = unit[len("·"):]
+		default:
+			return false
+		}
+	}
+}
+
+// scanDigits returns the index following the decimal digits of str from i
+func scanDigits(str string, i int) int {
+	for i < len(str) && str[i] >= '0' && str[i] <= '9' {
+		i++
+	}
+	return i
+}
+
+// scanLetters returns the index following the ASCII letters of str from i
+func scanLetters(str string, i int) int {
+	for i < len(str) && (str[i] >= 'a' && str[i] <= 'z' || str[i] >= 'A' && str[i] <= 'Z') {
+		i++
+	}
+	return i
+}
@@ -0,0 +1,58 @@
+package units
+
+import (
+	"math/rand"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var oldUnitRegex = regexp.MustCompile(`^(` + numberPattern + `)[ ]{0,1}(` + unitPattern + `)$`)
+
+func oldMatch(text []byte) (string, string, bool) {
+	str := strings.TrimSpace(string(text))
+	loc := oldUnitRegex.FindStringSubmatchIndex(str)
+	if loc == nil {
+		return "", "", false
+	}
+	return str[loc[2]:loc[3]], str[loc[4]:loc[5]], true
+}
+
+func TestScannerParity(t *testing.T) {
+	corpus := []string{
+		"1 V", "1V", "3.3 mV", "-1.2e3 Hz", "1e3Hz", "2eV", "1E-3V", "1e", "1.", ".5 V", "1.5.2 V", "1  V", " 1 V ",
+		"9.8 m/s^2", "3 N·m", "1 µm", "1 μm", "1 µµm", "50 %", "5 ‰", "5 %%", "1 m^", "1 m^-2", "1 m^-", "1 m/", "1 /s",
+		"+3 V", "++3 V", "-", "", "V", "3", "1e+5 Pa", "1e+ Pa", "1 m·", "1 kg·m^2/s^2", "1\tV", "1 V\n", "0x10 V",
+	}
+	alphabet := []string{"0", "1", "9", ".", "e", "E", "+", "-", " ", "V", "m", "µ", "μ", "/", "·", "^", "%", "‰", "x", "\t"}
+	r := rand.New(rand.NewSource(76))
+	for i := 0; i < 300000; i++ {
+		var b strings.Builder
+		for n := r.Intn(10); n >= 0; n-- {
+			b.WriteString(alphabet[r.Intn(len(alphabet))])
+		}
+		corpus = append(corpus, b.String())
+	}
+	for _, c := range corpus {
+		v1, u1, ok1 := oldMatch([]byte(c))
+		v2, u2, ok2 := matchUnit([]byte(c))
+		if v1 != v2 || u1 != u2 || ok1 != ok2 {
+			t.Fatalf("%q: regex (%q %q %v) scanner (%q %q %v)", c, v1, u1, ok1, v2, u2, ok2)
+		}
+	}
+	s, ok := scanUnit("-1.25e-3 mV")
+	if !ok || s.sign != "-" || s.integer != "1" || s.fraction != "25" || s.exponent != "-3" || s.unit != "mV" || s.value != "-1.25e-3" {
+		t.Fatal(s)
+	}
+}
+
+func FuzzScannerParity(f *testing.F) {
+	f.Add("1e3Hz")
+	f.Fuzz(func(t *testing.T, c string) {
+		v1, u1, ok1 := oldMatch([]byte(c))
+		v2, u2, ok2 := matchUnit([]byte(c))
+		if v1 != v2 || u1 != u2 || ok1 != ok2 {
+			t.Fatalf("%q: regex (%q %q %v) scanner (%q %q %v)", c, v1, u1, ok1, v2, u2, ok2)
+		}
+	})
+}
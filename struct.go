@@ -0,0 +1,80 @@
+package units
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// unitTag is the struct tag naming the unit of a field, ie. `unit:"Hz"`
+const unitTag = "unit"
+
+// MarshalStruct formats the `unit` tagged float fields of a struct (or pointer to struct) with
+// MarshalUnit, returning a map of field name to formatted value, ie. a field `Freq` tagged
+// `unit:"Hz"` becomes `{"Freq": "12.00 kHz"}`. Untagged and unexported fields are ignored.
+func MarshalStruct(v interface{}) (map[string]string, error) {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("units: MarshalStruct: unsupported type %T (must be a struct or pointer to struct)", v)
+	}
+
+	values := make(map[string]string)
+	err := walkStruct(rv, func(name, unit string, field reflect.Value) error {
+		text, err := MarshalUnit(unit, field.Float())
+		if err != nil {
+			return err
+		}
+		values[name] = string(text)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+// UnmarshalStruct parses values from a map of field name to formatted value into the `unit`
+// tagged float fields of the struct pointed to by v, as the inverse of MarshalStruct.
+// Fields missing from the map are left unchanged.
+func UnmarshalStruct(values map[string]string, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("units: UnmarshalStruct: unsupported type %T (must be a non-nil pointer to struct)", v)
+	}
+
+	return walkStruct(rv.Elem(), func(name, unit string, field reflect.Value) error {
+		text, ok := values[name]
+		if !ok {
+			return nil
+		}
+		value, err := UnmarshalUnit(unit, []byte(text))
+		if err != nil {
+			return err
+		}
+		field.SetFloat(value)
+		return nil
+	})
+}
+
+// walkStruct calls fn with the name, unit, and value of each exported `unit` tagged field,
+// annotating any error with the field name
+func walkStruct(rv reflect.Value, fn func(name, unit string, field reflect.Value) error) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		unit, ok := sf.Tag.Lookup(unitTag)
+		if !ok || unit == "-" || sf.PkgPath != "" {
+			continue
+		}
+
+		field := rv.Field(i)
+		if k := field.Kind(); k != reflect.Float32 && k != reflect.Float64 {
+			return fmt.Errorf("units: field %s: unsupported type %s (must be a float)", sf.Name, field.Type())
+		}
+
+		if err := fn(sf.Name, unit, field); err != nil {
+			return fmt.Errorf("units: field %s: %w", sf.Name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package units
+
+import (
+	"errors"
+	"testing"
+)
+
+type radio struct {
+	Freq    float64 `unit:"Hz"`
+	Power   float32 `unit:"W"`
+	Voltage float64 `unit:"V"`
+	Name    string
+	Skip    float64 `unit:"-"`
+	hidden  float64 `unit:"V"`
+}
+
+func TestStructCodec(t *testing.T) {
+	r := radio{Freq: 433.92e6, Power: 0.5, Voltage: 3.3, Name: "x", Skip: 1, hidden: 2}
+	m, err := MarshalStruct(&r)
+	if err != nil || len(m) != 3 || m["Freq"] != "433.92 MHz" || m["Power"] != "500.00 mW" || m["Voltage"] != "3.30 V" {
+		t.Fatal(m, err)
+	}
+	var back radio
+	if err := UnmarshalStruct(m, &back); err != nil {
+		t.Fatal(err)
+	}
+	if back.Freq != r.Freq || back.Power != r.Power || back.Voltage != r.Voltage {
+		t.Fatal(back)
+	}
+	err = UnmarshalStruct(map[string]string{"Freq": "1 V"}, &back)
+	if !errors.Is(err, ErrUnitMismatch) {
+		t.Fatal(err)
+	}
+	if err := UnmarshalStruct(m, back); err == nil {
+		t.Fatal("expected error for non-pointer")
+	}
+	if _, err := MarshalStruct(3); err == nil {
+		t.Fatal("expected error")
+	}
+	type bad struct {
+		N int `unit:"Hz"`
+	}
+	if _, err := MarshalStruct(bad{}); err == nil {
+		t.Fatal("expected error")
+	}
+}
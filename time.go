@@ -0,0 +1,61 @@
+package units
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// timeUnits are the time component units accepted by ParseTime in seconds
+var timeUnits = map[string]float64{
+	"ns": 1e-9,
+	"us": 1e-6,
+	"µs": 1e-6,
+	"μs": 1e-6,
+	"ms": 1e-3,
+	"s":  1,
+	"m":  60,
+	"h":  3600,
+}
+
+// timeRegex matches a single time component, ie. `30m` or `2.5 s`.
+// Multi character units precede `m` and `s` so `ms` is not read as minutes followed by seconds.
+var timeRegex = regexp.MustCompile(`^[ ]*([0-9]+(?:\.[0-9]+)?)[ ]{0,1}(ns|us|µs|μs|ms|s|m|h)`)
+
+// ParseTime parses a duration composed of one or more time components, ie. `500ms` or `1h30m`,
+// returning the sum of the components in seconds. Unlike SI parsing, `m` is minutes rather than
+// the milli prefix, with milliseconds written `ms`, and no other prefixes are accepted.
+// A leading sign applies to the whole duration, ie. `-1m30s` is -90 seconds.
+func ParseTime(text []byte) (seconds float64, err error) {
+	str := strings.TrimSpace(string(text))
+
+	sign := 1.0
+	if strings.HasPrefix(str, "-") {
+		sign, str = -1.0, str[1:]
+	} else if strings.HasPrefix(str, "+") {
+		str = str[1:]
+	}
+
+	if str == "" {
+		return 0.0, fmt.Errorf("%w: Time must be of the form 'ValueUnit[ValueUnit...]`, ie. '1h30m'", ErrMalformedValue)
+	}
+
+	// Sum components until the input is consumed
+	for str != "" {
+		matches := timeRegex.FindStringSubmatch(str)
+		if matches == nil {
+			return 0.0, fmt.Errorf("%w: Unable to parse time component: '%s' (units: ns, us, ms, s, m, h)", ErrMalformedValue, str)
+		}
+
+		value, err := strconv.ParseFloat(matches[1], 64)
+		if err != nil {
+			return 0.0, fmt.Errorf("%w: %v", ErrMalformedValue, err)
+		}
+
+		seconds += value * timeUnits[matches[2]]
+		str = str[len(matches[0]):]
+	}
+
+	return sign * seconds, nil
+}
@@ -0,0 +1,31 @@
+package units
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseTime(t *testing.T) {
+	cases := map[string]float64{
+		"500ms":    0.5,
+		"1h30m":    5400,
+		"2.5s":     2.5,
+		"1h 30m":   5400,
+		"-1m30s":   -90,
+		"10ns":     10e-9,
+		"3µs":      3e-6,
+		"1m500ms":  60.5,
+		" 250 us ": 250e-6,
+	}
+	for in, want := range cases {
+		got, err := ParseTime([]byte(in))
+		if err != nil || math.Abs(got-want) > 1e-12*math.Max(1, math.Abs(want)) {
+			t.Errorf("%q: %v %v", in, got, err)
+		}
+	}
+	for _, in := range []string{"", "5", "5Ks", "1h30", "abc", "-"} {
+		if _, err := ParseTime([]byte(in)); err == nil {
+			t.Errorf("%q: expected error", in)
+		}
+	}
+}
@@ -0,0 +1,330 @@
+package units
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Unit is a measurement value in the base (unprefixed) unit with the associated unit symbol
+type Unit struct {
+	Symbol string
+	Value  float64
+}
+
+// New creates a unit with the provided symbol and base value
+func New(symbol string, value float64) Unit {
+	return Unit{Symbol: symbol, Value: value}
+}
+
+// FromBase creates a unit with the provided symbol from the unscaled SI base value
+func FromBase(symbol string, base float64) Unit {
+	return Unit{Symbol: symbol, Value: base}
+}
+
+// Base returns the unscaled SI base value of the unit
+func (u Unit) Base() float64 {
+	return u.Value
+}
+
+// ParseUnit parses text into a Unit, detecting the unit symbol as with Parse
+func ParseUnit(text []byte) (Unit, error) {
+	value, _, symbol, err := Parse(text)
+	if err != nil {
+		return Unit{}, err
+	}
+	return Unit{Symbol: symbol, Value: value}, nil
+}
+
+// ParseFloatUnit parses text as with ParseUnit, additionally returning the base value
+// for callers that need both
+func ParseFloatUnit(text []byte) (base float64, u Unit, err error) {
+	u, err = ParseUnit(text)
+	if err != nil {
+		return 0.0, Unit{}, err
+	}
+	return u.Value, u, nil
+}
+
+// String formats a unit using MarshalUnit, or MarshalScalar for an empty Symbol, falling back
+// to plain formatting if the value cannot be represented with an SI prefix
+func (u Unit) String() string {
+	return Sprint(u.Symbol, u.Value)
+}
+
+// Compact formats a unit as with String with insignificant trailing zeros trimmed,
+// ie. `3.3 mV` rather than `3.30 mV` or `12 kHz` rather than `12.00 kHz`
+func (u Unit) Compact() string {
+	f := DefaultFormatter
+	f.TrimZeros = true
+	return f.Format(u.Symbol, u.Value)
+}
+
+// MarshalText implements encoding.TextMarshaler, using MarshalScalar for an empty Symbol
+// so zero value units are formatted as dimensionless values, ie. `0.00`
+func (u Unit) MarshalText() ([]byte, error) {
+	if u.Symbol == "" {
+		return MarshalScalar(u.Value)
+	}
+	return MarshalUnit(u.Symbol, u.Value)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, parsing against the unit Symbol.
+// If the Symbol is empty it is detected from the text, with bare numbers (ie. `3.30`) parsed
+// as dimensionless values.
+func (u *Unit) UnmarshalText(text []byte) error {
+	if u.Symbol == "" {
+		if trimmed := bytes.TrimSpace(text); numberRegex.Match(trimmed) {
+			value, err := strconv.ParseFloat(string(trimmed), 64)
+			if err != nil {
+				return fmt.Errorf("%w: %v", ErrMalformedValue, err)
+			}
+			u.Value = value
+			return nil
+		}
+
+		value, _, symbol, err := Parse(text)
+		if err != nil {
+			return err
+		}
+		u.Symbol, u.Value = symbol, value
+		return nil
+	}
+
+	value, err := UnmarshalUnit(u.Symbol, text)
+	if err != nil {
+		return err
+	}
+
+	u.Value = value
+
+	return nil
+}
+
+// InPrefix returns the mantissa of the unit when displayed with the provided prefix,
+// ie. 3.3 for `Unit{"V", 0.0033}` in `m`, without modifying the base Value
+func (u Unit) InPrefix(prefix string) (mantissa float64, err error) {
+	table := tableFor(u.Symbol)
+	order, ok := table.order(prefix)
+	if !ok {
+		return 0.0, fmt.Errorf("%w: Unrecognised SI prefix: '%s' (options: %s)", ErrUnknownPrefix, prefix, strings.Join(table.prefixes, ", "))
+	}
+	return u.Value / math.Pow10(order), nil
+}
+
+// RoundTo returns the unit with the base value rounded to the resolution of the provided prefix
+// and decimal places using the DefaultFormatter rounding mode, ie. 0.0033456 V to `m` with 1 decimal
+// is 0.0033 V, so the value is consistent with that displayed. Unrecognised prefixes return the unit unchanged.
+func (u Unit) RoundTo(prefix string, decimals int) Unit {
+	order, ok := tableFor(u.Symbol).order(prefix)
+	if !ok || checkFinite(u.Value) != nil {
+		return u
+	}
+	if decimals < 0 {
+		decimals = 0
+	}
+
+	// Round the mantissa in decimal and restore the order, ie. `3.3e-3`
+	var buf [32]byte
+	text := buf[:0]
+	if math.Signbit(u.Value) {
+		text = append(text, '-')
+	}
+	text = DefaultFormatter.Rounding.appendRound(text, math.Abs(u.Value/math.Pow10(order)), decimals)
+	text = append(text, 'e')
+	text = strconv.AppendInt(text, int64(order), 10)
+
+	value, _ := strconv.ParseFloat(string(text), 64)
+	return Unit{Symbol: u.Symbol, Value: value}
+}
+
+// Scan implements fmt.Scanner, consuming a single unit such as `3.3 mV` or `3.3mV` and parsing
+// it as with UnmarshalText. Scanning stops at the whitespace following the unit, leaving the
+// remaining input for subsequent scans.
+func (u *Unit) Scan(state fmt.ScanState, verb rune) error {
+	if verb != 'v' && verb != 's' {
+		return fmt.Errorf("units: Unit.Scan: unsupported verb: %%%c", verb)
+	}
+
+	notSpace := func(r rune) bool { return !unicode.IsSpace(r) }
+
+	// Read the value, which may already include the fused unit
+	token, err := state.Token(true, notSpace)
+	if err != nil {
+		return err
+	}
+	text := append([]byte(nil), token...)
+
+	// Otherwise the unit follows as a separate token
+	if _, _, ok := matchUnit(text); !ok {
+		token, err = state.Token(true, notSpace)
+		if err != nil {
+			return err
+		}
+		text = append(append(text, ' '), token...)
+	}
+
+	return u.UnmarshalText(text)
+}
+
+// MarshalJSON implements json.Marshaler, encoding the unit as an SI formatted string
+func (u Unit) MarshalJSON() ([]byte, error) {
+	text, err := u.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting either an SI formatted string
+// or a bare number interpreted as the base unit value
+func (u *Unit) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	if len(data) > 0 && data[0] != '"' {
+		var value float64
+		if err := json.Unmarshal(data, &value); err != nil {
+			return err
+		}
+		u.Value = value
+		return nil
+	}
+
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+
+	return u.UnmarshalText([]byte(text))
+}
+
+// Encode encodes the unit as exact decimal integer components for wire formats, such that
+// the value is mantissa * 10^exponent using the shortest decimal representation of the value,
+// ie. 3.3 is (33, -1) and 12000 is (12, 3). Non-finite values are encoded as (0, 0).
+func (u Unit) Encode() (symbol string, mantissa int64, exponent int32) {
+	if checkFinite(u.Value) != nil {
+		return u.Symbol, 0, 0
+	}
+
+	// Split the shortest scientific representation into digits and exponent, ie. `-3.3e+00`
+	var buf [32]byte
+	text := strconv.AppendFloat(buf[:0], u.Value, 'e', -1, 64)
+	i := bytes.IndexByte(text, 'e')
+	exp, _ := strconv.Atoi(string(text[i+1:]))
+
+	digits := bytes.Replace(text[:i], []byte("."), nil, 1)
+	mantissa, _ = strconv.ParseInt(string(digits), 10, 64)
+
+	// Shift the exponent by the number of fractional digits
+	fraction := len(bytes.TrimLeft(digits, "-")) - 1
+	return u.Symbol, mantissa, int32(exp - fraction)
+}
+
+// Decode decodes a unit from decimal integer components as produced by Encode,
+// with the value correctly rounded from the decimal representation
+func Decode(symbol string, mantissa int64, exponent int32) Unit {
+	var buf [32]byte
+	text := strconv.AppendInt(buf[:0], mantissa, 10)
+	text = append(text, 'e')
+	text = strconv.AppendInt(text, int64(exponent), 10)
+	value, _ := strconv.ParseFloat(string(text), 64)
+	return Unit{Symbol: symbol, Value: value}
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, encoding the symbol length as a uvarint
+// followed by the symbol bytes and the big endian IEEE 754 value
+func (u Unit) MarshalBinary() ([]byte, error) {
+	data := make([]byte, binary.MaxVarintLen64+len(u.Symbol)+8)
+	n := binary.PutUvarint(data, uint64(len(u.Symbol)))
+	n += copy(data[n:], u.Symbol)
+	binary.BigEndian.PutUint64(data[n:], math.Float64bits(u.Value))
+	return data[:n+8], nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, decoding units encoded by MarshalBinary
+func (u *Unit) UnmarshalBinary(data []byte) error {
+	length, n := binary.Uvarint(data)
+	if n <= 0 || length > uint64(len(data)) || uint64(len(data)-n) != length+8 {
+		return fmt.Errorf("%w: Invalid binary unit encoding of length %d", ErrMalformedValue, len(data))
+	}
+	data = data[n:]
+
+	u.Symbol = string(data[:length])
+	u.Value = math.Float64frombits(binary.BigEndian.Uint64(data[length:]))
+
+	return nil
+}
+
+// Add returns the sum of two units, returning an error if the unit symbols differ
+func (u Unit) Add(other Unit) (Unit, error) {
+	if u.Symbol != other.Symbol {
+		return Unit{}, fmt.Errorf("%w: Unable to add '%s' and '%s'", ErrUnitMismatch, u.Symbol, other.Symbol)
+	}
+	return Unit{Symbol: u.Symbol, Value: u.Value + other.Value}, nil
+}
+
+// Sub returns the difference of two units, returning an error if the unit symbols differ
+func (u Unit) Sub(other Unit) (Unit, error) {
+	if u.Symbol != other.Symbol {
+		return Unit{}, fmt.Errorf("%w: Unable to subtract '%s' from '%s'", ErrUnitMismatch, other.Symbol, u.Symbol)
+	}
+	return Unit{Symbol: u.Symbol, Value: u.Value - other.Value}, nil
+}
+
+// Delta returns the change from other to u (u - other) as a unit with the shared symbol,
+// ie. for display with Compact, returning an error if the unit symbols differ
+func (u Unit) Delta(other Unit) (Unit, error) {
+	if u.Symbol != other.Symbol {
+		return Unit{}, fmt.Errorf("%w: Unable to compute delta from '%s' to '%s'", ErrUnitMismatch, other.Symbol, u.Symbol)
+	}
+	return Unit{Symbol: u.Symbol, Value: u.Value - other.Value}, nil
+}
+
+// Mul returns the unit multiplied by a scalar
+func (u Unit) Mul(scalar float64) Unit {
+	return Unit{Symbol: u.Symbol, Value: u.Value * scalar}
+}
+
+// Neg returns the unit with the sign of its value flipped, with zero remaining unsigned
+// rather than becoming negative zero
+func (u Unit) Neg() Unit {
+	return Unit{Symbol: u.Symbol, Value: 0 - u.Value}
+}
+
+// Abs returns the unit with the absolute value
+func (u Unit) Abs() Unit {
+	return Unit{Symbol: u.Symbol, Value: math.Abs(u.Value)}
+}
+
+// Cmp compares the base values of two units, returning -1 if u < other, 0 if u == other,
+// and 1 if u > other. Unit symbols are not compared.
+func (u Unit) Cmp(other Unit) int {
+	switch {
+	case u.Value < other.Value:
+		return -1
+	case u.Value > other.Value:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// EqualTol reports whether two units have matching symbols and base values equal within
+// the provided relative tolerance of the larger magnitude
+func (u Unit) EqualTol(other Unit, relTol float64) bool {
+	if u.Symbol != other.Symbol {
+		return false
+	}
+	if u.Value == other.Value {
+		return true
+	}
+	return math.Abs(u.Value-other.Value) <= relTol*math.Max(math.Abs(u.Value), math.Abs(other.Value))
+}
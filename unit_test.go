@@ -0,0 +1,346 @@
+package units
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestUnitString(t *testing.T) {
+	var s fmt.Stringer = New("V", 0.0033)
+	if s.String() != "3.30 mV" || fmt.Sprint(s) != "3.30 mV" {
+		t.Error(s.String())
+	}
+}
+
+func TestUnitText(t *testing.T) {
+	var m encoding.TextMarshaler = New("V", 0.0033)
+	b, err := m.MarshalText()
+	if err != nil || string(b) != "3.30 mV" {
+		t.Fatal(string(b), err)
+	}
+	var u Unit
+	var um encoding.TextUnmarshaler = &u
+	if err := um.UnmarshalText(b); err != nil || u.Symbol != "V" || u.Value != 0.0033 {
+		t.Fatal(u, err)
+	}
+	u2 := Unit{Symbol: "Hz"}
+	if err := u2.UnmarshalText([]byte("12 kHz")); err != nil || u2.Value != 12000 {
+		t.Fatal(u2, err)
+	}
+	if err := u2.UnmarshalText([]byte("12 kV")); err == nil {
+		t.Fatal("mismatch")
+	}
+	u3 := Unit{}
+	if err := u3.UnmarshalText([]byte("5 m")); err != nil || u3.Symbol != "m" || u3.Value != 5 {
+		t.Fatal(u3, err)
+	}
+}
+
+func TestUnitJSON(t *testing.T) {
+	type S struct {
+		V Unit `json:"v"`
+		F Unit `json:"f"`
+	}
+	in := S{New("V", 0.0033), New("Hz", 12000)}
+	b, err := json.Marshal(in)
+	if err != nil || string(b) != `{"v":"3.30 mV","f":"12.00 kHz"}` {
+		t.Fatal(string(b), err)
+	}
+	var out S
+	if err := json.Unmarshal(b, &out); err != nil || out != in {
+		t.Fatal(out, err)
+	}
+	out = S{F: Unit{Symbol: "Hz"}}
+	if err := json.Unmarshal([]byte(`{"f": 50}`), &out); err != nil || out.F != New("Hz", 50) {
+		t.Fatal(out, err)
+	}
+}
+
+func TestUnitArithmetic(t *testing.T) {
+	a, b := New("V", 3), New("V", 1.5)
+	if s, err := a.Add(b); err != nil || s != New("V", 4.5) {
+		t.Error(s, err)
+	}
+	if s, err := a.Sub(b); err != nil || s != New("V", 1.5) {
+		t.Error(s, err)
+	}
+	if _, err := a.Add(New("A", 1)); !errors.Is(err, ErrUnitMismatch) {
+		t.Error(err)
+	}
+	if _, err := a.Sub(New("A", 1)); !errors.Is(err, ErrUnitMismatch) {
+		t.Error(err)
+	}
+	if a.Mul(2) != New("V", 6) || a.Cmp(b) != 1 || b.Cmp(a) != -1 || a.Cmp(a) != 0 {
+		t.Error("mul/cmp")
+	}
+}
+
+func TestParseUnit(t *testing.T) {
+	if u, err := ParseUnit([]byte("3.3 mV")); err != nil || u != (Unit{Symbol: "V", Value: 0.0033}) {
+		t.Error(u, err)
+	}
+	if u, err := ParseUnit([]byte("12 kHz")); err != nil || u != New("Hz", 12000) {
+		t.Error(u, err)
+	}
+	if u, err := ParseUnit([]byte("7 V")); err != nil || u != New("V", 7) {
+		t.Error(u, err)
+	}
+	if _, err := ParseUnit([]byte("garbage")); !errors.Is(err, ErrMalformedValue) {
+		t.Error(err)
+	}
+}
+
+func TestUnitEqualTol(t *testing.T) {
+	u := New("V", 0.0033)
+	p, _ := ParseUnit(MustMarshalUnit("V", 0.0033))
+	if !u.EqualTol(p, 1e-9) || !u.EqualTol(New("V", 0.00331), 0.01) {
+		t.Error("within")
+	}
+	if u.EqualTol(New("V", 0.0034), 0.01) || New("V", 0).EqualTol(New("V", 1e-12), 0.1) {
+		t.Error("outside")
+	}
+	if u.EqualTol(New("A", 0.0033), 1) {
+		t.Error("symbol")
+	}
+}
+
+func TestUnitScan(t *testing.T) {
+	var a, b, c Unit
+	n, err := fmt.Sscan("3.3 mV 12kHz  -1 A", &a, &b, &c)
+	if err != nil || n != 3 {
+		t.Fatal(n, err)
+	}
+	if a != (Unit{"V", 0.0033}) || b != (Unit{"Hz", 12000}) || c != (Unit{"A", -1}) {
+		t.Fatal(a, b, c)
+	}
+	x := Unit{Symbol: "Hz"}
+	var rest string
+	n, err = fmt.Sscanf("1.5 MHz tail", "%v %s", &x, &rest)
+	if err != nil || n != 2 || x.Value != 1.5e6 || rest != "tail" {
+		t.Fatal(n, err, x, rest)
+	}
+	y := Unit{Symbol: "Hz"}
+	if _, err := fmt.Sscan("3 V", &y); err == nil {
+		t.Fatal("expected mismatch")
+	}
+}
+
+func TestUnitInPrefix(t *testing.T) {
+	u := Unit{"V", 0.0033}
+	cases := map[string]float64{"m": 3.3, "u": 3300, "µ": 3300, "": 0.0033, "k": 0.0000033}
+	for p, want := range cases {
+		got, err := u.InPrefix(p)
+		if err != nil || !(Unit{"V", got}).EqualTol(Unit{"V", want}, 1e-12) {
+			t.Errorf("%q: %v %v", p, got, err)
+		}
+	}
+	if u.Value != 0.0033 {
+		t.Fatal("mutated")
+	}
+	if _, err := u.InPrefix("X"); !errors.Is(err, ErrUnknownPrefix) {
+		t.Fatal(err)
+	}
+}
+
+func TestUnitCompact(t *testing.T) {
+	cases := map[float64]string{
+		0.0033: "3.3 mV", 12000: "12 kV", 999.999: "1 kV", 1000: "1 kV", 0.001: "1 mV",
+		1.5e-9: "1.5 nV", 4.56e7: "45.6 MV", 0: "0 V", -0.25: "-250 mV", 999.4: "999.4 V",
+	}
+	for v, want := range cases {
+		if got := (Unit{"V", v}).Compact(); got != want {
+			t.Errorf("%v: %q want %q", v, got, want)
+		}
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	cases := []struct {
+		v float64
+		m int64
+		e int32
+	}{
+		{3.3, 33, -1}, {12000, 12, 3}, {0, 0, 0}, {-0.0033, -33, -4}, {1, 1, 0}, {0.1, 1, -1}, {123.456, 123456, -3},
+	}
+	for _, c := range cases {
+		s, m, e := Unit{"V", c.v}.Encode()
+		if s != "V" || m != c.m || e != c.e {
+			t.Errorf("%v: %v %v", c.v, m, e)
+		}
+		if d := Decode(s, m, e); d != (Unit{"V", c.v}) {
+			t.Errorf("%v: decoded %v", c.v, d)
+		}
+	}
+	r := rand.New(rand.NewSource(3))
+	for i := 0; i < 100000; i++ {
+		v := math.Float64frombits(r.Uint64())
+		if checkFinite(v) != nil {
+			continue
+		}
+		if d := Decode(Unit{"V", v}.Encode()); d.Value != v {
+			t.Fatalf("%v: %v", v, d.Value)
+		}
+	}
+	if _, m, e := (Unit{"V", math.NaN()}).Encode(); m != 0 || e != 0 {
+		t.Error(m, e)
+	}
+}
+
+func TestUnitNegAbs(t *testing.T) {
+	for _, c := range []struct{ v, neg, abs float64 }{{3.3, -3.3, 3.3}, {-2, 2, 2}, {0, 0, 0}} {
+		u := Unit{"V", c.v}
+		if n := u.Neg(); n.Symbol != "V" || n.Value != c.neg {
+			t.Error(n)
+		}
+		if a := u.Abs(); a.Symbol != "V" || a.Value != c.abs {
+			t.Error(a)
+		}
+	}
+	if s := (Unit{"V", -0.0033}).Abs().String(); s != "3.30 mV" {
+		t.Error(s)
+	}
+	for _, v := range []float64{0, math.Copysign(0, -1)} {
+		if s := (Unit{"V", v}).Neg().String(); s != "0.00 V" {
+			t.Error(v, s)
+		}
+	}
+}
+
+func TestParseFloatUnit(t *testing.T) {
+	cases := map[string]Unit{"3.3 mV": {"V", 0.0033}, "12 kHz": {"Hz", 12000}, "4.7 kOhm": {"Ohm", 4700}, "2 Mbps": {"bps", 2e6}}
+	for in, want := range cases {
+		base, u, err := ParseFloatUnit([]byte(in))
+		if err != nil || base != u.Value || u != want {
+			t.Errorf("%q: %v %v %v", in, base, u, err)
+		}
+	}
+	if _, _, err := ParseFloatUnit([]byte("bad")); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestUnitBase(t *testing.T) {
+	for _, v := range []float64{0.0033, 12000, 0, -5} {
+		u := FromBase("V", v)
+		if u.Base() != v || u.Symbol != "V" || u != New("V", v) {
+			t.Error(u)
+		}
+	}
+	if s := FromBase("V", 0.0033).String(); s != "3.30 mV" {
+		t.Error(s)
+	}
+}
+
+func TestUnitBinary(t *testing.T) {
+	units := []Unit{{"V", 0.0033}, {"", 42}, {"Hz", 12345.678901234}, {"µm/s^2", -1e-300}, {"x", math.Inf(1)}}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(units); err != nil {
+		t.Fatal(err)
+	}
+	var back []Unit
+	if err := gob.NewDecoder(&buf).Decode(&back); err != nil {
+		t.Fatal(err)
+	}
+	if len(back) != len(units) {
+		t.Fatal(back)
+	}
+	for i := range units {
+		if back[i] != units[i] {
+			t.Error(back[i], units[i])
+		}
+	}
+	data, _ := Unit{"V", 1}.MarshalBinary()
+	var u Unit
+	for _, bad := range [][]byte{nil, data[:len(data)-1], append(data, 0), {0x80}} {
+		if err := u.UnmarshalBinary(bad); !errors.Is(err, ErrMalformedValue) {
+			t.Error(bad, err)
+		}
+	}
+}
+
+func TestUnitBinaryHugeLength(t *testing.T) {
+	var u Unit
+	if err := u.UnmarshalBinary([]byte{0xf8, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01}); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestUnitDelta(t *testing.T) {
+	d, err := Unit{"V", 3.3}.Delta(Unit{"V", 3.2})
+	if err != nil || d.Symbol != "V" || d.Compact() != "100 mV" {
+		t.Fatal(d, err)
+	}
+	d, err = Unit{"Hz", 1000}.Delta(Unit{"Hz", 3000})
+	if err != nil || d != (Unit{"Hz", -2000}) || d.Compact() != "-2 kHz" {
+		t.Fatal(d, err)
+	}
+	if _, err := (Unit{"V", 1}).Delta(Unit{"A", 1}); !errors.Is(err, ErrUnitMismatch) {
+		t.Fatal(err)
+	}
+}
+
+func TestUnitRoundTo(t *testing.T) {
+	cases := []struct {
+		v        float64
+		prefix   string
+		decimals int
+		want     float64
+	}{
+		{0.0033456, "m", 1, 0.0033},
+		{0.0033456, "m", 2, 0.00335},
+		{0.0033456, "u", 0, 0.003346},
+		{0.0033456, "", 2, 0},
+		{12345.678, "k", 1, 12300},
+		{12345.678, "", 0, 12346},
+		{-0.0033456, "m", 1, -0.0033},
+		{1.5e9, "G", 0, 2e9},
+	}
+	for _, c := range cases {
+		r := Unit{"V", c.v}.RoundTo(c.prefix, c.decimals)
+		if r.Symbol != "V" || r.Value != c.want {
+			t.Errorf("%v %q %d: %v want %v", c.v, c.prefix, c.decimals, r.Value, c.want)
+		}
+	}
+	if r := (Unit{"V", 1.23}).RoundTo("x", 1); r.Value != 1.23 {
+		t.Error(r)
+	}
+	if r := (Unit{"V", math.NaN()}).RoundTo("m", 1); !math.IsNaN(r.Value) {
+		t.Error(r)
+	}
+	u := Unit{"V", 0.0033456}.RoundTo("m", 1)
+	if u.String() != "3.30 mV" {
+		t.Error(u.String())
+	}
+}
+
+func TestUnitEmptySymbol(t *testing.T) {
+	if s := (Unit{Value: 12000}).String(); s != "12.00 k" {
+		t.Errorf("String: %q", s)
+	}
+	if s := (Unit{}).String(); s != "0.00" {
+		t.Errorf("String: %q", s)
+	}
+
+	type S struct{ U Unit }
+	b, err := json.Marshal(S{})
+	if err != nil || string(b) != `{"U":"0.00"}` {
+		t.Fatal(string(b), err)
+	}
+	var out S
+	if err := json.Unmarshal(b, &out); err != nil || out != (S{}) {
+		t.Fatal(out, err)
+	}
+	if err := json.Unmarshal([]byte(`{"U":"3.30"}`), &out); err != nil || out.U != (Unit{Value: 3.3}) {
+		t.Fatal(out, err)
+	}
+	if err := json.Unmarshal([]byte(`{"U":"3.3 mV"}`), &out); err != nil || out.U != (Unit{"V", 0.0033}) {
+		t.Fatal(out, err)
+	}
+}
@@ -1,99 +1,721 @@
 package units
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 	"math"
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
 )
 
-// Prefixes are SI prefixes for encoding and decoding
-var Prefixes = []string{"p", "n", "u", "m", "", "K", "M", "G", "T"}
+// Prefixes are SI prefixes for encoding and decoding. Kilo is the SI lowercase `k`, with the
+// uppercase `K` accepted when parsing for compatibility (see PrefixAlias to emit `K`).
+var Prefixes = []string{"q", "r", "y", "z", "a", "f", "p", "n", "u", "m", "", "k", "M", "G", "T", "P", "E", "Z", "Y", "R", "Q"}
 
 // Orders are the associated orders for each prefix
-var Orders = []int{-12, -9, -6, -3, 0, 3, 6, 9, 12}
+var Orders = []int{-30, -27, -24, -21, -18, -15, -12, -9, -6, -3, 0, 3, 6, 9, 12, 15, 18, 21, 24, 27, 30}
 
-var prefixMap map[string]int
-var orderMap map[int]string
+// PrefixInfo describes a supported SI prefix
+type PrefixInfo struct {
+	Symbol string
+	Order  int64
+	Factor float64
+}
 
-func init() {
-	prefixMap := make(map[string]int)
-	orderMap := make(map[int]string)
-	for i := range Prefixes {
-		prefixMap[Prefixes[i]] = Orders[i]
-		orderMap[Orders[i]] = Prefixes[i]
+// SupportedPrefixes returns a copy of the supported SI prefixes and their orders and factors,
+// in ascending order
+func SupportedPrefixes() []PrefixInfo {
+	table := siPrefixes()
+	info := make([]PrefixInfo, len(table.prefixes))
+	for i, p := range table.prefixes {
+		order := table.prefixMap[p]
+		info[i] = PrefixInfo{Symbol: p, Order: int64(order), Factor: math.Pow10(order)}
+	}
+	return info
+}
+
+// pseudoUnits are dimensionless units scaled by a fixed factor rather than SI prefixes,
+// mapping each unit to its multiplier from a fraction, ie. 0.125 is `12.50 %`
+var pseudoUnits = map[string]float64{
+	"%": 100,
+	"‰": 1000,
+}
+
+// prefixAliases are alternate spellings accepted when parsing prefixes, mapped to the canonical prefix
+var prefixAliases = map[string]string{
+	"µ": "u", // U+00B5 micro sign
+	"μ": "u", // U+03BC greek small letter mu
+	"K": "k", // uppercase kilo, as formerly emitted
+}
+
+// canonicalPrefix returns the canonical spelling of a parsed prefix
+func canonicalPrefix(prefix string) string {
+	if p, ok := prefixAliases[prefix]; ok {
+		return p
+	}
+	return prefix
+}
+
+// parsePrefixes are the non-thousand SI prefixes accepted when parsing SI units, ie. `5 cm`.
+// These are never produced when marshalling, nor detected by Parse where they would be ambiguous
+// with unit symbols (ie. `cd` or `dB`).
+var parsePrefixes = map[string]int{
+	"da": 1,
+	"h":  2,
+	"d":  -1,
+	"c":  -2,
+}
+
+// siTable is the prefix table for SI units
+var siTable *prefixTable
+var siOnce sync.Once
+
+// siPrefixes lazily builds the SI prefix table on first use,
+// after which it is safe for concurrent reads
+func siPrefixes() *prefixTable {
+	siOnce.Do(func() {
+		siTable = newPrefixTable(Prefixes, Orders)
+	})
+	return siTable
+}
+
+// defaultStep is the exponent step between SI prefix orders
+const defaultStep = 3
+
+// scale calculates the prefix order (multiple of step) for a value, returning the scaled mantissa and order
+func scale(value float64, step int) (float64, int) {
+	if value == 0 || math.IsNaN(value) || math.IsInf(value, 0) {
+		return value, 0
+	}
+
+	// Calculate exponent and snap down to the nearest prefix order (multiple of step)
+	exponent := int(math.Floor(math.Log10(math.Abs(value))))
+	order := exponent - (((exponent % step) + step) % step)
+
+	// Correct for floating point error in the logarithm so the mantissa is within [1, 10^step),
+	// using exact powers of ten so values on a boundary (ie. 1000) always select the larger prefix
+	mantissa := value / math.Pow10(order)
+	if math.Abs(mantissa) >= math.Pow10(step) {
+		order += step
+		mantissa = value / math.Pow10(order)
+	} else if math.Abs(mantissa) < 1 {
+		order -= step
+		mantissa = value / math.Pow10(order)
+	}
+
+	return mantissa, order
+}
+
+// clampScale scales a value as with scale, clamping the order to the range of the prefix table
+// so values beyond the smallest or largest prefix are represented within that prefix
+func clampScale(table *prefixTable, value float64, step int) (float64, int) {
+	mantissa, order := scale(value, step)
+	if order < table.minOrder || order > table.maxOrder {
+		if order < table.minOrder {
+			order = table.minOrder
+		} else {
+			order = table.maxOrder
+		}
+		mantissa = value / math.Pow10(order)
 	}
+	return mantissa, order
 }
 
-// MarshalUnit is a helper for common (SI) unit serialisation/marshalling
+// ScaleToPrefix scales a value to the nearest SI prefix, returning the mantissa and prefix.
+// Values outside the supported prefix range are clamped to the smallest or largest prefix.
+func ScaleToPrefix(value float64) (mantissa float64, prefix string) {
+	table := siPrefixes()
+	mantissa, order := clampScale(table, value, defaultStep)
+	return mantissa, table.orderMap[order]
+}
+
+// ConvertPrefix restates a value from one SI prefix to another, ie. 2.5 from `M` to `k` is 2500
+func ConvertPrefix(value float64, from, to string) (float64, error) {
+	table := siPrefixes()
+	fromOrder, ok := table.order(from)
+	if !ok {
+		return 0.0, fmt.Errorf("%w: Unrecognised SI prefix: '%s' (options: %s)", ErrUnknownPrefix, from, strings.Join(table.prefixes, ", "))
+	}
+	toOrder, ok := table.order(to)
+	if !ok {
+		return 0.0, fmt.Errorf("%w: Unrecognised SI prefix: '%s' (options: %s)", ErrUnknownPrefix, to, strings.Join(table.prefixes, ", "))
+	}
+
+	return value * math.Pow10(fromOrder-toOrder), nil
+}
+
+// Factor returns the multiplication factor for an SI prefix, ie. 1e-3 for `m` or 1 for the
+// empty prefix, or false if the prefix is not recognised
+func Factor(prefix string) (float64, bool) {
+	order, ok := siPrefixes().order(prefix)
+	if !ok {
+		return 0.0, false
+	}
+
+	// Parse the factor so it is the nearest float64 to the power of ten (ie. 1e-30)
+	factor, _ := strconv.ParseFloat("1e"+strconv.Itoa(order), 64)
+	return factor, true
+}
+
+// OrderOf returns the SI order (multiple of 3) of the prefix MarshalUnit selects for a value,
+// ie. 3 for 12000 or -6 for 0.000002, including where rounding carries into the next prefix
+// (ie. 3 for 999.999). Values beyond the prefix table are clamped to the smallest or largest order.
+// Zero and non-finite values have an order of 0.
+func OrderOf(value float64) int64 {
+	f := DefaultFormatter
+	f.ClampPrefix = true
+
+	var buf [32]byte
+	_, prefix, _, err := f.appendDetailed(buf[:0], "", value)
+	if err != nil {
+		return 0
+	}
+	order, _ := siPrefixes().order(prefix)
+	return int64(order)
+}
+
+// NiceStep returns a rounded 1/2/5 style axis step dividing the magnitude of maxValue into at
+// most the provided number of ticks, ie. 2000 for (10000, 5), along with the SI prefix of the
+// largest tick for labelling the axis. Zero, non-finite maxima or fewer than one tick return (0, "").
+func NiceStep(maxValue float64, ticks int) (step float64, prefix string) {
+	maxValue = math.Abs(maxValue)
+	if maxValue == 0 || ticks < 1 || checkFinite(maxValue) != nil {
+		return 0.0, ""
+	}
+
+	// Round the raw step up to the next 1, 2, or 5 multiple of a power of ten
+	raw := maxValue / float64(ticks)
+	exponent := int(math.Floor(math.Log10(raw)))
+	nice := 10.0
+	for _, n := range []float64{1, 2, 5} {
+		if n*math.Pow10(exponent) >= raw {
+			nice = n
+			break
+		}
+	}
+
+	// Divide for negative exponents as negative powers of ten are inexact
+	if exponent < 0 {
+		step = nice / math.Pow10(-exponent)
+	} else {
+		step = nice * math.Pow10(exponent)
+	}
+
+	table := siPrefixes()
+	_, order := clampScale(table, math.Ceil(maxValue/step)*step, defaultStep)
+	return step, table.orderMap[order]
+}
+
+// checkFinite returns an error for NaN and infinite values which cannot be formatted
+func checkFinite(value float64) error {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return fmt.Errorf("%w: Unable to format value: %v", ErrNotFinite, value)
+	}
+	return nil
+}
+
+// MarshalUnit is a helper for common (SI) unit serialisation/marshalling.
+// The prefix is selected so the rounded mantissa is within [1, 1000), so boundary values take the
+// larger prefix (ie. 1000 is `1.00 k` and 0.001 is `1.00 m`) as do values that round up to a
+// boundary (ie. 999.999 is `1.00 k`). Prefix selection uses the magnitude of the value, with
+// the sign carried on the mantissa (ie. -0.0033 is `-3.30 m`). An empty unit returns ErrEmptyUnit
+// as this is likely a caller error, with dimensionless values formatted using MarshalScalar.
 func MarshalUnit(unit string, value float64) ([]byte, error) {
-	// Calculate exponent
-	exponent := 0
-	if math.Abs(value) > 1 {
-		for divided := value; math.Abs(divided) < 10.0; divided = divided / 10.0 {
-			exponent++
+	text, _, _, err := MarshalUnitDetailed(unit, value)
+	return text, err
+}
+
+// WriteUnit writes the SI formatted value and unit to w as with MarshalUnit,
+// returning the number of bytes written and any formatting or write error
+func WriteUnit(w io.Writer, unit string, value float64) (int, error) {
+	buf := writeBuffers.Get().(*[]byte)
+	defer writeBuffers.Put(buf)
+
+	text, err := AppendUnit((*buf)[:0], unit, value)
+	*buf = text[:0]
+	if err != nil {
+		return 0, err
+	}
+
+	return w.Write(text)
+}
+
+// writeBuffers are reused formatting buffers for WriteUnit
+var writeBuffers = sync.Pool{
+	New: func() interface{} {
+		buf := make([]byte, 0, 64)
+		return &buf
+	},
+}
+
+// MarshalUnitDetailed marshals a value as with MarshalUnit, additionally returning the
+// selected prefix and the scaled (unrounded) mantissa, ie. for labelling chart axes
+func MarshalUnitDetailed(unit string, value float64) (text []byte, prefix string, scaled float64, err error) {
+	if err := checkUnit(unit); err != nil {
+		return nil, "", 0, err
+	}
+	return DefaultFormatter.appendDetailed(nil, unit, value)
+}
+
+// MarshalScalar is a helper for dimensionless SI serialisation/marshalling with only a prefix,
+// ie. `12.00 k` for 12000 or `3.30` for 3.3
+func MarshalScalar(value float64) ([]byte, error) {
+	text, err := DefaultFormatter.append(nil, "", value)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(text, " "), nil
+}
+
+// MarshalCount is a helper for serialisation/marshalling of unitless counts with SI prefixes,
+// ie. `1.20 M` for 1200000 or `999.00` for 999. Fractional counts are not given sub-unit prefixes,
+// so 0.5 is `0.50` rather than `500.00 m`.
+func MarshalCount(value float64) ([]byte, error) {
+	f := DefaultFormatter
+	if !f.HasMinOrder || f.MinOrder < 0 {
+		f.MinOrder, f.HasMinOrder = 0, true
+	}
+
+	text, err := f.append(nil, "", value)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(text, " "), nil
+}
+
+// checkUnit returns an error for empty units, which are likely caller errors
+func checkUnit(unit string) error {
+	if unit == "" {
+		return fmt.Errorf("%w: Unable to marshal without a unit (see MarshalScalar for dimensionless values)", ErrEmptyUnit)
+	}
+	return nil
+}
+
+// Explain describes how a value is formatted by MarshalUnit for troubleshooting, including the
+// selected order, prefix, and unrounded mantissa, ie. `value 12000: order=3 prefix=k mantissa=12 -> '12.00 kHz'`
+func Explain(unit string, value float64) string {
+	text, prefix, mantissa, err := MarshalUnitDetailed(unit, value)
+	if err != nil {
+		return fmt.Sprintf("value %g: error: %v", value, err)
+	}
+	order, _ := tableFor(unit).order(prefix)
+	return fmt.Sprintf("value %g: order=%d prefix=%s mantissa=%g -> '%s'", value, order, prefix, mantissa, text)
+}
+
+// Sprint formats a value as with MarshalUnit for trusted inputs where an error is inconvenient,
+// or MarshalScalar for an empty unit. Values that cannot be formatted are rendered unscaled with %g,
+// ie. `NaN V` or `+Inf V`.
+func Sprint(unit string, value float64) string {
+	if unit == "" {
+		text, err := MarshalScalar(value)
+		if err != nil {
+			return fmt.Sprintf("%g", value)
 		}
+		return string(text)
+	}
+
+	text, err := MarshalUnit(unit, value)
+	if err != nil {
+		return fmt.Sprintf("%g %s", value, unit)
+	}
+	return string(text)
+}
+
+// AppendUnit appends the SI formatted value and unit to dst as with MarshalUnit,
+// returning the extended buffer (or dst unchanged on error)
+func AppendUnit(dst []byte, unit string, value float64) ([]byte, error) {
+	if err := checkUnit(unit); err != nil {
+		return dst, err
+	}
+	return DefaultFormatter.append(dst, unit, value)
+}
+
+// MarshalRate is a helper for rate unit serialisation/marshalling, with the prefix selected from
+// and applied to the numerator unit while the denominator is never prefixed, ie. 12000 m/s is `12.00 km/s`
+func MarshalRate(numUnit, denUnit string, value float64) ([]byte, error) {
+	if err := checkUnit(numUnit); err != nil {
+		return nil, err
+	}
+	if err := checkUnit(denUnit); err != nil {
+		return nil, err
+	}
+	text, err := DefaultFormatter.append(nil, numUnit, value)
+	if err != nil {
+		return nil, err
+	}
+	return append(append(text, '/'), denUnit...), nil
+}
+
+// MarshalUnitPrec is a helper for common (SI) unit serialisation/marshalling
+// with the provided number of decimal places
+func MarshalUnitPrec(unit string, value float64, precision int) ([]byte, error) {
+	if err := checkUnit(unit); err != nil {
+		return nil, err
+	}
+	f := DefaultFormatter
+	f.Precision = precision
+	return f.append(nil, unit, value)
+}
+
+// MarshalUnitWithPrefix is a helper for common (SI) unit serialisation/marshalling using
+// the provided prefix rather than automatically selecting one, ie. for aligning columns of values
+func MarshalUnitWithPrefix(unit, prefix string, value float64, precision int) ([]byte, error) {
+	if err := checkUnit(unit); err != nil {
+		return nil, err
+	}
+	f := DefaultFormatter
+	f.Precision = precision
+	return f.appendWithPrefix(nil, unit, prefix, value)
+}
+
+// MarshalUnitSigFigs is a helper for common (SI) unit serialisation/marshalling that preserves
+// at least the provided number of significant figures, shifting down to smaller prefixes (and larger
+// mantissas) where the two decimal mantissa of the nearest prefix would under-resolve the value,
+// ie. 1234.5 Hz with 5 significant figures is `1234.50 Hz` rather than `1.23 kHz`. Prefixes are not
+// shifted beyond a mantissa of 2^53, past which float64 values carry no further resolution.
+func MarshalUnitSigFigs(unit string, value float64, sigFigs int) ([]byte, error) {
+	text, prefix, mantissa, err := MarshalUnitDetailed(unit, value)
+	if err != nil || value == 0 {
+		return text, err
+	}
+	if _, ok := pseudoUnits[unit]; ok || leadingPower(unit) != 1 {
+		return text, nil
+	}
+
+	// Count the rounded mantissa digits, as the text may be localised
+	table := tableFor(unit)
+	order, _ := table.order(prefix)
+	for significantDigits(DefaultFormatter.Rounding.appendRound(nil, math.Abs(mantissa), DefaultFormatter.Precision)) < sigFigs {
+		smaller, ok := table.orderMap[order-DefaultFormatter.step()]
+		if !ok || math.Abs(value/math.Pow10(order-DefaultFormatter.step())) > maxExactInteger {
+			break
+		}
+		order -= DefaultFormatter.step()
+		mantissa = value / math.Pow10(order)
+
+		text, err = MarshalUnitWithPrefix(unit, smaller, value, DefaultFormatter.Precision)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return text, nil
+}
+
+// MarshalUnitSig is a helper for common (SI) unit serialisation/marshalling with the provided total
+// number of significant figures across the mantissa, rather than a fixed number of decimal places,
+// ie. with 4 significant figures 12346 Hz is `12.35 kHz` and 0.00012346 Hz is `123.5 uHz`.
+// Unlike MarshalUnitSigFigs the prefix is selected as with MarshalUnit.
+func MarshalUnitSig(unit string, value float64, sig int) ([]byte, error) {
+	if err := checkUnit(unit); err != nil {
+		return nil, err
+	}
+	if sig < 1 {
+		return nil, fmt.Errorf("Invalid significant figures: %d (must be positive)", sig)
+	}
+	if err := checkFinite(value); err != nil {
+		return nil, err
+	}
+
+	// Round to the significant figures prior to scaling, so values rounding up to a boundary
+	// take the larger prefix, then find the displayed mantissa
+	var mantissa float64
+	if factor, ok := pseudoUnits[unit]; ok {
+		mantissa = roundSig(value*factor, sig)
+		value = mantissa / factor
+	} else if leadingPower(unit) != 1 {
+		value = roundSig(value, sig)
+		mantissa = value
 	} else {
-		for multiplied := value; math.Abs(multiplied) > 1.0; multiplied = multiplied * 10.0 {
-			exponent--
+		value = roundSig(value, sig)
+		mantissa, _ = scale(value, DefaultFormatter.step())
+	}
+
+	// Allocate the remaining significant figures to decimal places
+	f := DefaultFormatter
+	f.Precision = sig - 1
+	if mantissa != 0 {
+		f.Precision -= int(math.Floor(math.Log10(math.Abs(mantissa))))
+	}
+	if f.Precision < 0 {
+		f.Precision = 0
+	}
+
+	return f.append(nil, unit, value)
+}
+
+// roundSig rounds a value to the provided number of significant figures
+func roundSig(value float64, sig int) float64 {
+	rounded, _ := strconv.ParseFloat(strconv.FormatFloat(value, 'e', sig-1, 64), 64)
+	return rounded
+}
+
+// significantDigits counts the significant digits in the leading number of formatted text,
+// stopping at the first character that is not a digit, decimal point, or sign
+func significantDigits(text []byte) int {
+	count := 0
+	for _, c := range text {
+		if (c < '0' || c > '9') && c != '.' && c != '-' && c != '+' {
+			break
+		}
+		if c >= '1' && c <= '9' || (c == '0' && count > 0) {
+			count++
 		}
 	}
+	return count
+}
 
-	prefixExponent := (exponent % 3) * 3
-	prefix, ok := orderMap[prefixExponent]
-	if !ok {
-		return nil, fmt.Errorf("Unsupported prefix for exponent 10^%d", prefixExponent)
+// MarshalUnitStrict is a helper for common (SI) unit serialisation/marshalling with the
+// provided number of decimal places, returning an error if the formatted value differs from
+// the original by more than the provided epsilon of relative accuracy
+func MarshalUnitStrict(unit string, value float64, precision int, epsilon float64) ([]byte, error) {
+	if err := checkUnit(unit); err != nil {
+		return nil, err
+	}
+	f := DefaultFormatter
+	f.Precision = precision
+	text, prefix, mantissa, err := f.appendDetailed(nil, unit, value)
+	if err != nil {
+		return nil, err
 	}
 
-	str := fmt.Sprintf("%.2f %s%s", value/math.Pow(10, float64(exponent)), prefix, unit)
+	// Determine the loss from the rounded mantissa and prefix, as the text may be localised
+	if value != 0 {
+		if loss := math.Abs(f.roundedValue(unit, prefix, mantissa)-value) / math.Abs(value); loss > epsilon {
+			return nil, fmt.Errorf("%w: '%s' differs from %g by %g (epsilon: %g)", ErrLossyFormat, text, value, loss, epsilon)
+		}
+	}
 
-	return []byte(str), nil
+	return text, nil
 }
 
-// UnitRegex matches unit strings of the form `[numerator].[denominator] [prefix][unit]` ie. `10.2 dBmV`
-var unitRegex = regexp.MustCompile(`^([\-]?[0-9\.]+)[ ]{0,1}([a-zA-Z]+)$`)
+// roundedValue returns the value represented by a mantissa with the provided prefix (or the factor
+// of a pseudo-unit) once rounded to the formatter precision
+func (f Formatter) roundedValue(unit, prefix string, mantissa float64) float64 {
+	rounded, _ := strconv.ParseFloat(string(f.Rounding.appendRound(nil, math.Abs(mantissa), f.Precision)), 64)
+	if math.Signbit(mantissa) {
+		rounded = -rounded
+	}
+	if factor, ok := pseudoUnits[unit]; ok {
+		return rounded / factor
+	}
+	order, _ := parseOrder(tableFor(unit), prefix)
+	return rounded * math.Pow10(order*leadingPower(unit))
+}
 
-// UnmarshalUnit is a helper for common (SI) unit deserialisation/unmarshalling
-func UnmarshalUnit(unit string, text []byte) (float64, error) {
+// numberPattern matches signed decimal values with an optional exponent, ie. `-1.2e3`.
+// The mantissa and exponent are signed independently, so `-1.5e-3` and `+1.5e+3` are both accepted.
+// The exponent is consumed greedily where digits follow, so fused units such as `1e3Hz` are
+// 1000 Hz while `2eV` (without exponent digits) remains 2 eV.
+const numberPattern = `[+\-]?[0-9]+(?:\.[0-9]+)?(?:[eE][+\-]?[0-9]+)?`
+
+// unitPattern matches prefixed (optionally compound) units and pseudo-units, ie. `kHz`, `m/s^2` or `%`
+const unitPattern = `[µμ]?[a-zA-Z]+(?:\^-?[0-9]+)?(?:[/·][a-zA-Z]+(?:\^-?[0-9]+)?)*|%|‰`
 
-	// Match on UnitRegex to check for sane strings
-	matches := unitRegex.FindStringSubmatch(string(text))
-	if matches == nil {
-		return 0.0, fmt.Errorf("Unit must be of the form 'Value PrefixUnit`, ie. '100.2 K%s'", unit)
+// leadingPower returns the power of the leading component of a (compound) unit, ie. 2 for `m^2/s`,
+// to which a prefix on the unit also applies, ie. `1 km^2` is 10^6 m^2
+func leadingPower(unit string) int {
+	end := strings.IndexAny(unit, "/·")
+	if end < 0 {
+		end = len(unit)
+	}
+	i := strings.IndexByte(unit[:end], '^')
+	if i < 0 {
+		return 1
+	}
+	power, err := strconv.Atoi(unit[i+1 : end])
+	if err != nil {
+		return 1
 	}
+	return power
+}
 
-	// Split value and unit
-	valueString := matches[1]
-	unitString := matches[2]
+// numberRegex matches bare values without a unit
+var numberRegex = regexp.MustCompile(`^` + numberPattern + `$`)
 
-	// Check suffix matches
-	if !strings.HasSuffix(unitString, unit) {
-		return 0.0, fmt.Errorf("Unable to parse unit: '%s' expected suffix: '%s'", unitString, unit)
+// matchUnit trims surrounding whitespace and scans text with scanUnit,
+// returning the value and unit strings or false if the text is not sane
+func matchUnit(text []byte) (valueString, unitString string, ok bool) {
+	s, ok := scanUnit(strings.TrimSpace(string(text)))
+	if !ok {
+		return "", "", false
 	}
+	return s.value, s.unit, true
+}
 
-	// Strip suffix and calculate order from prefix
-	prefix := strings.TrimSuffix(unitString, unit)
-	order := 0
-	if prefix != "" {
-		for i := range Prefixes {
-			if prefix == Prefixes[i] {
-				order = Orders[i]
-			}
+// splitPrefix splits a unit string into the longest recognised (canonical) prefix and the remaining
+// symbol, which must start with a letter so compound units such as `m/s` or `m^2` are not split
+func splitPrefix(unitString string) (prefix, symbol string) {
+	match := ""
+	for i := range Prefixes {
+		p := Prefixes[i]
+		if len(p) > len(match) && strings.HasPrefix(unitString, p) && startsWithLetter(unitString[len(p):]) {
+			match, prefix = p, p
 		}
-		if order == 0 {
-			return 0.0, fmt.Errorf("Unrecognised SI prefix: '%s' (options: %s)", prefix, strings.Join(Prefixes, ", "))
+	}
+	for alias, p := range prefixAliases {
+		if len(alias) > len(match) && strings.HasPrefix(unitString, alias) && startsWithLetter(unitString[len(alias):]) {
+			match, prefix = alias, p
 		}
 	}
+	return prefix, strings.TrimPrefix(unitString, match)
+}
+
+// startsWithLetter reports whether a string starts with a letter
+func startsWithLetter(s string) bool {
+	r, _ := utf8.DecodeRuneInString(s)
+	return unicode.IsLetter(r)
+}
+
+// UnmarshalUnit is a helper for common (SI) unit deserialisation/unmarshalling
+func UnmarshalUnit(unit string, text []byte) (float64, error) {
+	return Parser{}.Unmarshal(unit, text)
+}
+
+// UnmarshalUnitExact is a helper for common (SI) unit deserialisation/unmarshalling as with
+// UnmarshalUnit, additionally requiring the text to carry the expected prefix, ie. `k` for `12 kHz`.
+// Equivalent spellings of a prefix (ie. `K` for `k` or `µ` for `u`) are accepted.
+func UnmarshalUnitExact(unit, expectedPrefix string, text []byte) (float64, error) {
+	expected, ok := parseOrder(tableFor(unit), expectedPrefix)
+	if _, pseudo := pseudoUnits[unit]; !ok || (pseudo && expectedPrefix != "") {
+		return 0.0, fmt.Errorf("%w: Unrecognised expected prefix: '%s' for unit: '%s'", ErrUnknownPrefix, expectedPrefix, unit)
+	}
 
-	// Parse floating point component
-	base, err := strconv.ParseFloat(valueString, 64)
+	_, order, _, _, err := Parser{}.split(unit, text)
 	if err != nil {
 		return 0.0, err
 	}
+	if order != expected*leadingPower(unit) {
+		return 0.0, fmt.Errorf("%w: Unable to parse unit: '%s' expected prefix: '%s'", ErrPrefixMismatch, bytes.TrimSpace(text), expectedPrefix)
+	}
+
+	return UnmarshalUnit(unit, text)
+}
+
+// UnmarshalUnits parses a batch of texts sharing an expected unit, such as a CSV column,
+// returning an error annotated with the index of the first failing row, ie. `row 42: ...`
+func UnmarshalUnits(unit string, texts [][]byte) ([]float64, error) {
+	values := make([]float64, len(texts))
+	for i := range texts {
+		value, err := UnmarshalUnit(unit, texts[i])
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", i, err)
+		}
+		values[i] = value
+	}
+	return values, nil
+}
 
-	// Multiply by prefix order
-	value := base * math.Pow(10, float64(order))
+// UnmarshalValue is a helper for unit deserialisation/unmarshalling where the unit suffix is optional,
+// bare numbers (ie. `3.3`) are interpreted as the base unit while suffixed values must match the unit
+func UnmarshalValue(unit string, text []byte) (float64, error) {
+	trimmed := bytes.TrimSpace(text)
+	if !numberRegex.Match(trimmed) {
+		return UnmarshalUnit(unit, text)
+	}
+
+	value, err := strconv.ParseFloat(string(trimmed), 64)
+	if err != nil {
+		return 0.0, fmt.Errorf("%w: %v", ErrMalformedValue, err)
+	}
 
 	return value, nil
 }
+
+// uncertaintySeps are the separators between a value and its uncertainty accepted by ParseUncertain
+var uncertaintySeps = []string{"±", "+/-"}
+
+// ParseUncertain parses a value with an uncertainty, ie. `3.3 ± 0.1 V` or `3.3 +/- 0.1 mV`, with the
+// unit following the uncertainty applying to both. Either side may also carry its own unit, ie.
+// `3.3 mV ± 100 uV`. Text without an uncertainty is parsed as with UnmarshalUnit with an uncertainty of 0.
+func ParseUncertain(unit string, text []byte) (value, uncertainty float64, err error) {
+	s := string(text)
+	for _, sep := range uncertaintySeps {
+		i := strings.Index(s, sep)
+		if i < 0 {
+			continue
+		}
+		valueText, uncertaintyText := strings.TrimSpace(s[:i]), s[i+len(sep):]
+
+		uncertainty, err = UnmarshalUnit(unit, []byte(uncertaintyText))
+		if err != nil {
+			return 0.0, 0.0, err
+		}
+		if uncertainty < 0 {
+			return 0.0, 0.0, fmt.Errorf("%w: Uncertainty must not be negative: '%s'", ErrMalformedValue, strings.TrimSpace(uncertaintyText))
+		}
+
+		// Bare values share the (prefixed) unit of the uncertainty
+		if numberRegex.MatchString(valueText) {
+			_, unitString, _ := matchUnit([]byte(uncertaintyText))
+			valueText += " " + unitString
+		}
+		value, err = UnmarshalUnit(unit, []byte(valueText))
+		if err != nil {
+			return 0.0, 0.0, err
+		}
+
+		return value, uncertainty, nil
+	}
+
+	value, err = UnmarshalUnit(unit, text)
+	if err != nil {
+		return 0.0, 0.0, err
+	}
+	return value, 0.0, nil
+}
+
+// LenientJunk are the characters stripped by ParseLenient, it may be modified to configure
+// the stripped characters though this is not goroutine safe so should only be done during initialisation.
+var LenientJunk = "\"'`*;"
+
+// ParseLenient parses SI unit text with the expected unit as with UnmarshalUnit, first stripping
+// any LenientJunk characters such as surrounding quotes or trailing punctuation, ie. `"3.3 mV";`
+func ParseLenient(unit string, text []byte) (float64, error) {
+	cleaned := bytes.Map(func(r rune) rune {
+		if strings.ContainsRune(LenientJunk, r) {
+			return -1
+		}
+		return r
+	}, text)
+
+	return UnmarshalUnit(unit, cleaned)
+}
+
+// Validate checks text is a well formed unit string for the expected unit, returning nil
+// where UnmarshalUnit would succeed and the same error otherwise
+func Validate(unit string, text []byte) error {
+	_, err := UnmarshalUnit(unit, text)
+	return err
+}
+
+// Parse parses a unit string without a known unit, returning the base value, the detected
+// SI prefix, and the remaining unit symbol. The longest matching prefix is used while leaving
+// at least one character for the unit, so `3.3 mV` is (0.0033, "m", "V") and `3.3 m` is (3.3, "", "m").
+func Parse(text []byte) (value float64, prefix string, unit string, err error) {
+	return Parser{}.Parse(text)
+}
+
+// MustMarshalUnit is like MarshalUnit but panics if the value cannot be marshalled
+func MustMarshalUnit(unit string, value float64) []byte {
+	text, err := MarshalUnit(unit, value)
+	if err != nil {
+		panic(fmt.Errorf("units: MustMarshalUnit(%q, %v): %w", unit, value, err))
+	}
+	return text
+}
+
+// MustUnmarshalUnit is like UnmarshalUnit but panics if the text cannot be parsed,
+// simplifying initialisation of package level values and test fixtures
+func MustUnmarshalUnit(unit string, text []byte) float64 {
+	value, err := UnmarshalUnit(unit, text)
+	if err != nil {
+		panic(fmt.Errorf("units: MustUnmarshalUnit(%q, %q): %w", unit, text, err))
+	}
+	return value
+}
@@ -0,0 +1,1068 @@
+package units
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"math"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestMarshalUnitRoundTrip(t *testing.T) {
+	cases := []struct {
+		unit  string
+		value float64
+		want  string
+	}{
+		{"Hz", 12000, "12.00 kHz"},
+		{"V", 0.0033, "3.30 mV"},
+		{"V", 0, "0.00 V"},
+		{"V", -0.0033, "-3.30 mV"},
+		{"Hz", 1.5e9, "1.50 GHz"},
+		{"F", 4.7e-12, "4.70 pF"},
+		{"V", 1, "1.00 V"},
+		{"V", 999, "999.00 V"},
+	}
+	for _, c := range cases {
+		got, err := MarshalUnit(c.unit, c.value)
+		if err != nil || string(got) != c.want {
+			t.Errorf("MarshalUnit(%v) = %q, %v want %q", c.value, got, err, c.want)
+		}
+		v, err := UnmarshalUnit(c.unit, got)
+		if err != nil || (c.value != 0 && math.Abs(v-c.value)/math.Abs(c.value) > 0.01) {
+			t.Errorf("round trip %v -> %v %v", c.value, v, err)
+		}
+	}
+}
+
+func TestPrefixMaps(t *testing.T) {
+	table := siPrefixes()
+	for _, p := range []string{"k", "K"} {
+		if order, ok := table.order(p); !ok || order != 3 {
+			t.Errorf("order(%q) = %d, %v", p, order, ok)
+		}
+	}
+	if p := table.orderMap[3]; p != "k" {
+		t.Errorf("orderMap[3] = %q", p)
+	}
+	if _, err := UnmarshalUnit("V", []byte("3.3 XV")); err == nil {
+		t.Errorf("expected error for unknown prefix")
+	}
+	if v, err := UnmarshalUnit("V", []byte("3.3 mV")); err != nil || math.Abs(v-3.3e-3) > 1e-15 {
+		t.Errorf("got %v, %v", v, err)
+	}
+}
+
+func TestExtendedPrefixes(t *testing.T) {
+	b, err := MarshalUnit("V", 2.5e18)
+	if err != nil || string(b) != "2.50 EV" {
+		t.Fatalf("%q %v", b, err)
+	}
+	v, err := UnmarshalUnit("V", b)
+	if err != nil || math.Abs(v-2.5e18)/2.5e18 > 1e-12 {
+		t.Fatalf("%v %v", v, err)
+	}
+	b, _ = MarshalUnit("m", 1.5e-30)
+	if string(b) != "1.50 qm" {
+		t.Fatalf("%q", b)
+	}
+}
+
+func TestMarshalUnitPrec(t *testing.T) {
+	for _, c := range []struct {
+		v    float64
+		p    int
+		want string
+	}{{12000, 0, "12 kHz"}, {12000, 2, "12.00 kHz"}, {12345.678, 6, "12.345678 kHz"}, {0.0033, 6, "3.300000 mHz"}} {
+		b, err := MarshalUnitPrec("Hz", c.v, c.p)
+		if err != nil || string(b) != c.want {
+			t.Errorf("%v %d: %q %v", c.v, c.p, b, err)
+		}
+	}
+	if _, err := MarshalUnitPrec("Hz", 1, -1); err == nil {
+		t.Error("neg")
+	}
+}
+
+func TestUnmarshalExponent(t *testing.T) {
+	for _, c := range []struct {
+		s    string
+		want float64
+	}{{"1.2e3 Hz", 1200}, {"1E-6 Hz", 1e-6}, {"1e+3 kHz", 1e6}, {"1e3Hz", 1000}} {
+		v, err := UnmarshalUnit("Hz", []byte(c.s))
+		if err != nil || v != c.want {
+			t.Errorf("%s %v %v", c.s, v, err)
+		}
+	}
+	if _, err := UnmarshalUnit("Hz", []byte("1e Hz")); err == nil {
+		t.Error("malformed")
+	}
+}
+
+func TestUnmarshalWhitespace(t *testing.T) {
+	for _, s := range []string{"+3.3 V", " +3.3 V ", "\t3.3V\t", "  3.3 V"} {
+		v, err := UnmarshalUnit("V", []byte(s))
+		if err != nil || v != 3.3 {
+			t.Errorf("%q %v %v", s, v, err)
+		}
+	}
+	if v, _ := UnmarshalBinary("B", []byte(" +1 KiB ")); v != 1024 {
+		t.Error(v)
+	}
+}
+
+func TestMarshalUnitStrict(t *testing.T) {
+	if _, err := MarshalUnitStrict("Hz", 1234.5678, 2, 1e-6); !errors.Is(err, ErrLossyFormat) {
+		t.Error(err)
+	}
+	if _, err := MarshalUnitStrict("Hz", 1234.5678, 4, 1e-9); !errors.Is(err, ErrLossyFormat) {
+		t.Error(err)
+	}
+	if b, err := MarshalUnitStrict("Hz", 1234.5678, 7, 1e-9); err != nil || string(b) != "1.2345678 kHz" {
+		t.Error(string(b), err)
+	}
+	if b, err := MarshalUnitStrict("Hz", 1234.5678, 2, 1e-2); err != nil || string(b) != "1.23 kHz" {
+		t.Error(string(b), err)
+	}
+	if b, err := MarshalUnitStrict("%", 0.12345, 1, 1e-2); err != nil || string(b) != "12.3 %" {
+		t.Error(string(b), err)
+	}
+	saved := DefaultFormatter
+	defer func() { DefaultFormatter = saved }()
+	DefaultFormatter.DecimalSep = ","
+	DefaultFormatter.UnitCase = CaseLower
+	if b, err := MarshalUnitStrict("V", 1.5, 2, 1e-6); err != nil || string(b) != "1,50 v" {
+		t.Error(string(b), err)
+	}
+	if _, err := MarshalUnitStrict("V", 1.2345, 2, 1e-6); !errors.Is(err, ErrLossyFormat) {
+		t.Error(err)
+	}
+}
+
+func TestScaleToPrefix(t *testing.T) {
+	for _, c := range []struct {
+		v float64
+		m float64
+		p string
+	}{{0.0033, 3.3, "m"}, {1000, 1, "k"}, {0.001, 1, "m"}, {999, 999, ""}, {1e15, 1, "P"}, {1e30, 1, "Q"}, {4.7e-12, 4.7, "p"}, {-2e6, -2, "M"}, {0, 0, ""}, {1e-33, 0.001, "q"}} {
+		m, p := ScaleToPrefix(c.v)
+		if p != c.p || math.Abs(m-c.m) > 1e-9*math.Abs(c.m) {
+			t.Errorf("%v: %v %q", c.v, m, p)
+		}
+	}
+}
+
+func TestOrderOf(t *testing.T) {
+	for _, c := range []struct {
+		v float64
+		o int64
+	}{
+		{12000, 3}, {0.000002, -6}, {0, 0}, {-12000, 3}, {-0.5, -3}, {1, 0}, {999, 0}, {1e9, 9},
+		{999.999, 3}, {-999.999, 3}, {0.000999999, -3}, {1e40, 30}, {1e-40, -30},
+		{math.NaN(), 0}, {math.Inf(1), 0},
+	} {
+		if o := OrderOf(c.v); o != c.o {
+			t.Errorf("%v: %v", c.v, o)
+		}
+	}
+
+	// The order matches the prefix selected by MarshalUnit
+	for _, v := range []float64{0.0033, 999.995, 999.994, 12345, 4.7e-12, 1e30, 9.99999e29} {
+		_, prefix, _, err := MarshalUnitDetailed("V", v)
+		order, _ := siPrefixes().order(prefix)
+		if err != nil || OrderOf(v) != int64(order) {
+			t.Errorf("%v: OrderOf %d, MarshalUnit prefix %q (%v)", v, OrderOf(v), prefix, err)
+		}
+	}
+}
+
+func TestMarshalUnitWithPrefix(t *testing.T) {
+	if b, err := MarshalUnitWithPrefix("V", "m", 3.3, 1); err != nil || string(b) != "3300.0 mV" {
+		t.Error(string(b), err)
+	}
+	if b, err := MarshalUnitWithPrefix("V", "", 0.5, 2); err != nil || string(b) != "0.50 V" {
+		t.Error(string(b), err)
+	}
+	if _, err := MarshalUnitWithPrefix("V", "x", 3.3, 1); !errors.Is(err, ErrUnknownPrefix) {
+		t.Error(err)
+	}
+}
+
+func TestMarshalUnitCarry(t *testing.T) {
+	for v, w := range map[float64]string{999.999: "1.00 kV", 999.994: "999.99 V", -999.999: "-1.00 kV", 0.000999999: "1.00 mV"} {
+		b, err := MarshalUnit("V", v)
+		if err != nil || string(b) != w {
+			t.Error(v, string(b), err)
+		}
+	}
+}
+
+func FuzzRoundTrip(f *testing.F) {
+	for _, v := range []float64{0, 1, 999.999, 0.0009999, 1000, 12000, -3.3e-3, 1e30} {
+		f.Add(v, uint8(0))
+	}
+	units := []string{"V", "Hz", "A", "m", "Ohm"}
+	f.Fuzz(func(t *testing.T, value float64, u uint8) {
+		unit := units[int(u)%len(units)]
+		text, err := MarshalUnit(unit, value)
+		if err != nil {
+			return
+		}
+		parsed, err := UnmarshalUnit(unit, text)
+		if err != nil {
+			t.Fatalf("%v -> %q: %v", value, text, err)
+		}
+		m, _ := ScaleToPrefix(parsed)
+		if math.Abs(m) >= 1000 || (value != 0 && math.Abs(m) < 1 && math.Abs(parsed) > 1e-30) {
+			t.Fatalf("%v -> %q mantissa %v", value, text, m)
+		}
+		if value != 0 && math.Abs(parsed-value)/math.Abs(value) > 0.005+1e-9 {
+			t.Fatalf("%v -> %q -> %v", value, text, parsed)
+		}
+	})
+}
+
+func TestMarshalNotFinite(t *testing.T) {
+	for _, v := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		if _, err := MarshalUnit("V", v); !errors.Is(err, ErrNotFinite) {
+			t.Error(v, err)
+		}
+		if _, err := MarshalBinary("B", v); !errors.Is(err, ErrNotFinite) {
+			t.Error(v, err)
+		}
+		if _, err := MarshalUnitWithPrefix("V", "m", v, 2); !errors.Is(err, ErrNotFinite) {
+			t.Error(v, err)
+		}
+	}
+	if b, _ := MarshalUnit("V", 0); string(b) != "0.00 V" {
+		t.Error(string(b))
+	}
+}
+
+func TestParse(t *testing.T) {
+	for _, c := range []struct {
+		s, p, u string
+		v       float64
+	}{{"3.3 mV", "m", "V", 0.0033}, {"12 kHz", "k", "Hz", 12000}, {"12 Hz", "", "Hz", 12}, {"5 m", "", "m", 5}, {"5 mm", "m", "m", 0.005}} {
+		v, p, u, err := Parse([]byte(c.s))
+		if err != nil || v != c.v || p != c.p || u != c.u {
+			t.Error(c.s, v, p, u, err)
+		}
+	}
+	if _, _, _, err := Parse([]byte("junk")); err == nil {
+		t.Error("junk")
+	}
+}
+
+func TestMarshalOutOfRange(t *testing.T) {
+	for _, v := range []float64{1e-33, 5e-324, 1e33, 999.999e30, -1e40} {
+		if _, err := MarshalUnit("V", v); !errors.Is(err, ErrOutOfRange) {
+			t.Error(v, err)
+		}
+	}
+	if m, p := ScaleToPrefix(1e-33); p != "q" || math.Abs(m-0.001) > 1e-12 {
+		t.Error(m, p)
+	}
+	if m, p := ScaleToPrefix(5e33); p != "Q" || math.Abs(m-5000) > 1e-9 {
+		t.Error(m, p)
+	}
+	if _, p := ScaleToPrefix(math.Inf(1)); p != "" {
+		t.Error(p)
+	}
+}
+
+// TestConcurrentUse exercises the lazily built prefix tables from many goroutines, run with -race
+func TestConcurrentUse(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 64; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				b, err := MarshalUnit("V", float64(i*j)*1e-3)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if _, err := UnmarshalUnit("V", b); err != nil {
+					t.Error(err)
+				}
+				if v, err := UnmarshalBinary("B", []byte("1 KiB")); err != nil || v != 1024 {
+					t.Error(v, err)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestMust(t *testing.T) {
+	if MustUnmarshalUnit("V", []byte("3.3 V")) != 3.3 || string(MustMarshalUnit("V", 3.3)) != "3.30 V" {
+		t.Error("must")
+	}
+	func() {
+		defer func() {
+			r := recover()
+			err, ok := r.(error)
+			if !ok || !errors.Is(err, ErrUnitMismatch) || !strings.Contains(err.Error(), "expected suffix") {
+				t.Error(r)
+			}
+		}()
+		MustUnmarshalUnit("V", []byte("3.3 A"))
+	}()
+	func() {
+		defer func() {
+			err, _ := recover().(error)
+			if !errors.Is(err, ErrNotFinite) {
+				t.Error(err)
+			}
+		}()
+		MustMarshalUnit("V", math.NaN())
+	}()
+}
+
+func TestCompoundUnits(t *testing.T) {
+	for _, c := range []struct {
+		u, s string
+		v    float64
+	}{{"m/s", "12 m/s", 12}, {"m/s", "12 km/s", 12000}, {"m/s^2", "9.8 m/s^2", 9.8}, {"N·m", "3 N·m", 3}, {"N·m", "3 mN·m", 0.003}, {"m^2", "2 m^2", 2}, {"m^2", "1 km^2", 1e6}, {"m^3", "2 mm^3", 2e-9}, {"m^2/s", "3 km^2/s", 3e6}, {"s^-1", "5 Ms^-1", 5e-6}} {
+		v, err := UnmarshalUnit(c.u, []byte(c.s))
+		if err != nil || math.Abs(v-c.v) > 1e-12*math.Abs(c.v) {
+			t.Error(c, v, err)
+		}
+	}
+	if _, err := UnmarshalUnit("m/s", []byte("12 m/h")); err == nil {
+		t.Error("mismatch")
+	}
+	if _, err := UnmarshalUnit("m/s", []byte("12 m//s")); err == nil {
+		t.Error("malformed")
+	}
+	if b, _ := MarshalUnit("m/s", 12000); string(b) != "12.00 km/s" {
+		t.Error(string(b))
+	}
+	for v, w := range map[float64]string{12000: "12000.00 m^2", 0.5: "0.50 m^2"} {
+		if b, err := MarshalUnit("m^2", v); err != nil || string(b) != w {
+			t.Error(string(b), err, w)
+		}
+	}
+	if b, err := MarshalUnitWithPrefix("m^2", "k", 3e6, 2); err != nil || string(b) != "3.00 km^2" {
+		t.Error(string(b), err)
+	}
+	if _, err := UnmarshalUnitExact("m^2", "k", []byte("1 km^2")); err != nil {
+		t.Error(err)
+	}
+	for _, c := range []struct {
+		s, p, u string
+		v       float64
+	}{{"12 km/s", "k", "m/s", 12000}, {"12 m/s", "", "m/s", 12}, {"2 m^2", "", "m^2", 2}, {"2 km^2", "k", "m^2", 2e6}, {"3 mN·m", "m", "N·m", 0.003}} {
+		if v, p, u, err := Parse([]byte(c.s)); v != c.v || p != c.p || u != c.u || err != nil {
+			t.Error(c, v, p, u, err)
+		}
+	}
+	if _, _, _, err := (Parser{RequireKnown: true}).Parse([]byte("3 m/s")); !errors.Is(err, ErrUnknownUnit) || !strings.Contains(err.Error(), "'m/s'") {
+		t.Error(err)
+	}
+}
+
+func BenchmarkUnmarshalUnit(b *testing.B) {
+	b.ReportAllocs()
+	text := []byte("12.34 kHz")
+	for i := 0; i < b.N; i++ {
+		if _, err := UnmarshalUnit("Hz", text); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkMarshalUnit(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := MarshalUnit("Hz", 12345.6); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestAppendUnit(t *testing.T) {
+	buf := make([]byte, 0, 64)
+	buf, err := AppendUnit(buf, "V", 0.0033)
+	if err != nil || string(buf) != "3.30 mV" {
+		t.Fatal(string(buf), err)
+	}
+	p := &buf[:1][0]
+	buf = append(buf, ", "...)
+	buf, _ = AppendUnit(buf, "Hz", 12000)
+	if string(buf) != "3.30 mV, 12.00 kHz" || &buf[0] != p {
+		t.Fatal(string(buf))
+	}
+	out, err := AppendUnit(buf, "V", math.NaN())
+	if err == nil || string(out) != string(buf) {
+		t.Fatal(string(out))
+	}
+	if n := testing.AllocsPerRun(100, func() { buf, _ = AppendUnit(buf[:0], "V", 12.3) }); n != 0 {
+		t.Error("allocs", n)
+	}
+}
+
+func BenchmarkAppendUnit(b *testing.B) {
+	b.ReportAllocs()
+	buf := make([]byte, 0, 64)
+	for i := 0; i < b.N; i++ {
+		buf, _ = AppendUnit(buf[:0], "Hz", 12345.6)
+	}
+}
+
+func TestMicroSign(t *testing.T) {
+	var vals []float64
+	for _, s := range []string{"3.3 uV", "3.3 µV", "3.3 μV", "3.3µV"} {
+		v, err := UnmarshalUnit("V", []byte(s))
+		if err != nil {
+			t.Error(s, err)
+		}
+		vals = append(vals, v)
+		pv, p, u, err := Parse([]byte(s))
+		if err != nil || p != "u" || u != "V" || pv != v {
+			t.Error(s, pv, p, u, err)
+		}
+	}
+	for _, v := range vals {
+		if v != vals[0] {
+			t.Error(vals)
+		}
+	}
+	if s := (Formatter{Precision: 2, MicroSign: true}).Format("V", 3.3e-6); s != "3.30 µV" {
+		t.Error(s)
+	}
+	if v, _ := UnmarshalUnit("V", []byte("3.30 µV")); v != vals[0] {
+		t.Error(v)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := Validate("V", []byte("3.3 mV")); err != nil {
+		t.Error(err)
+	}
+	for s, e := range map[string]error{"3.3 mA": ErrUnitMismatch, "3.3 xV": ErrUnknownPrefix, "3..3 V": ErrMalformedValue, "V": ErrMalformedValue} {
+		if err := Validate("V", []byte(s)); !errors.Is(err, e) {
+			t.Error(s, err)
+		}
+	}
+}
+
+func TestPseudoUnits(t *testing.T) {
+	if v, err := UnmarshalUnit("%", []byte("12.5 %")); err != nil || v != 0.125 {
+		t.Error(v, err)
+	}
+	if v, err := UnmarshalUnit("‰", []byte("3‰")); err != nil || v != 0.003 {
+		t.Error(v, err)
+	}
+	if b, _ := MarshalUnit("%", 0.125); string(b) != "12.50 %" {
+		t.Error(string(b))
+	}
+	if b, _ := MarshalUnit("‰", 0.003); string(b) != "3.00 ‰" {
+		t.Error(string(b))
+	}
+	if b, _ := MarshalUnit("%", 25); string(b) != "2500.00 %" {
+		t.Error(string(b))
+	}
+	if _, err := UnmarshalUnit("%", []byte("12.5 V")); err == nil {
+		t.Error("mismatch")
+	}
+}
+
+func TestMarshalUnitDetailed(t *testing.T) {
+	for _, v := range []float64{0.0033, 12000, -4.7e-12, 999.999, 0} {
+		text, prefix, scaled, err := MarshalUnitDetailed("V", v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, p, _, _ := Parse(text)
+		m, _ := ScaleToPrefix(MustUnmarshalUnit("V", text))
+		if p != prefix || math.Abs(m-scaled) > 0.005 {
+			t.Error(v, string(text), prefix, scaled, p, m)
+		}
+		b, _ := MarshalUnit("V", v)
+		if string(b) != string(text) {
+			t.Error(string(b))
+		}
+	}
+}
+
+func TestUnmarshalMalformedNumbers(t *testing.T) {
+	for _, s := range []string{"1.2.3 V", ".5 V", "5. V", "1..2 V", "- V"} {
+		_, err := UnmarshalUnit("V", []byte(s))
+		if !errors.Is(err, ErrMalformedValue) || !strings.Contains(err.Error(), "must be of the form") {
+			t.Error(s, err)
+		}
+	}
+	if v, err := UnmarshalUnit("V", []byte("0.5 V")); err != nil || v != 0.5 {
+		t.Error(v, err)
+	}
+}
+
+func TestWriteUnit(t *testing.T) {
+	var b bytes.Buffer
+	n, err := WriteUnit(&b, "V", 0.0033)
+	m, _ := MarshalUnit("V", 0.0033)
+	if err != nil || n != len(m) || b.String() != string(m) {
+		t.Error(n, err, b.String())
+	}
+	if n, err := WriteUnit(&b, "V", math.NaN()); err == nil || n != 0 {
+		t.Error(n, err)
+	}
+	w := bufio.NewWriter(&b)
+	if n := testing.AllocsPerRun(10, func() { WriteUnit(w, "Hz", 12000) }); n != 0 {
+		t.Error("allocs", n)
+	}
+}
+
+func TestMarshalUnitSigFigs(t *testing.T) {
+	for _, c := range []struct {
+		v    float64
+		n    int
+		want string
+	}{{1234.5, 5, "1234.50 Hz"}, {1234.5, 3, "1.23 kHz"}, {12345678, 6, "12345.68 kHz"}, {1.5e-30, 8, "1.50 qHz"}, {0, 5, "0.00 Hz"}, {-1234.5, 5, "-1234.50 Hz"}, {100, 4, "100.00 Hz"},
+		{1234.5, 40, "1234500000000000.00 pHz"}, {1e-25, 40, "100000.00 qHz"}} {
+		b, err := MarshalUnitSigFigs("Hz", c.v, c.n)
+		if err != nil || string(b) != c.want {
+			t.Error(c, string(b), err)
+		}
+	}
+	if b, err := MarshalUnitSigFigs("m/s^2", 9.8, 4); err != nil || string(b) != "9800.00 mm/s^2" {
+		t.Error(string(b), err)
+	}
+	saved := DefaultFormatter
+	defer func() { DefaultFormatter = saved }()
+	DefaultFormatter.NoSpace = true
+	if b, err := MarshalUnitSigFigs("m/s^2", 9.8, 4); err != nil || string(b) != "9800.00mm/s^2" {
+		t.Error(string(b), err)
+	}
+	DefaultFormatter.NoSpace = false
+	DefaultFormatter.TrimZeros = true
+	if b, err := MarshalUnitSigFigs("Hz", 1200, 3); err != nil || string(b) != "1.2 kHz" {
+		t.Error(string(b), err)
+	}
+}
+
+func TestUnmarshalValue(t *testing.T) {
+	for s, w := range map[string]float64{"3.3": 3.3, " -2e3 ": -2000, "3.3 V": 3.3, "3.3 mV": 0.0033} {
+		if v, err := UnmarshalValue("V", []byte(s)); err != nil || v != w {
+			t.Error(s, v, err)
+		}
+	}
+	if _, err := UnmarshalValue("V", []byte("3.3 A")); !errors.Is(err, ErrUnitMismatch) {
+		t.Error(err)
+	}
+	for _, s := range []string{"NaN", "Inf", "0x1F", ""} {
+		if _, err := UnmarshalValue("V", []byte(s)); !errors.Is(err, ErrMalformedValue) {
+			t.Error(s, err)
+		}
+	}
+}
+
+func TestSupportedPrefixes(t *testing.T) {
+	info := SupportedPrefixes()
+	if len(info) != len(Prefixes) {
+		t.Fatal(len(info))
+	}
+	for i := range info {
+		if info[i].Symbol != Prefixes[i] || info[i].Order != int64(Orders[i]) {
+			t.Error(info[i])
+		}
+	}
+	if info[11].Symbol != "k" || info[11].Factor != 1000 {
+		t.Error(info[11])
+	}
+	info[0].Symbol = "X"
+	if SupportedPrefixes()[0].Symbol != "q" || Prefixes[0] != "q" {
+		t.Error("mutated")
+	}
+}
+
+func TestMarshalUnitBoundaries(t *testing.T) {
+	for v, w := range map[float64]string{1: "1.00 V", 999.999: "1.00 kV", 1000: "1.00 kV", 0.001: "1.00 mV", 0.0009999: "999.90 uV", 0.9999999: "1.00 V"} {
+		if b, _ := MarshalUnit("V", v); string(b) != w {
+			t.Error(v, string(b))
+		}
+	}
+	for _, o := range Orders {
+		for _, v := range []float64{math.Pow(10, float64(o)), math.Pow10(o), 1000 * math.Pow10(o-3)} {
+			b, _, _, err := MarshalUnitDetailed("V", v)
+			if err != nil || string(b) != "1.00 "+siPrefixes().orderMap[o]+"V" {
+				t.Error(o, v, string(b), err)
+			}
+		}
+	}
+}
+
+func TestSprint(t *testing.T) {
+	if s := Sprint("V", 0.0033); s != "3.30 mV" {
+		t.Error(s)
+	}
+	if s := Sprint("V", math.NaN()); s != "NaN V" {
+		t.Error(s)
+	}
+	if s := Sprint("V", math.Inf(-1)); s != "-Inf V" {
+		t.Error(s)
+	}
+	if s := New("V", math.NaN()).String(); s != "NaN V" {
+		t.Error(s)
+	}
+}
+
+func TestConvertPrefix(t *testing.T) {
+	for _, c := range []struct {
+		v        float64
+		from, to string
+		want     float64
+	}{{2.5, "M", "k", 2500}, {2500, "k", "M", 2.5}, {3.3, "m", "m", 3.3}, {3.3, "", "m", 3300}, {1, "µ", "n", 1000}} {
+		if v, err := ConvertPrefix(c.v, c.from, c.to); err != nil || v != c.want {
+			t.Error(c, v, err)
+		}
+	}
+	if _, err := ConvertPrefix(1, "x", "k"); !errors.Is(err, ErrUnknownPrefix) {
+		t.Error(err)
+	}
+	if _, err := ConvertPrefix(1, "k", "x"); !errors.Is(err, ErrUnknownPrefix) {
+		t.Error(err)
+	}
+}
+
+func TestMarshalNegative(t *testing.T) {
+	cases := map[float64]string{
+		-0.0033:    "-3.30 mV",
+		-12000:     "-12.00 kV",
+		-999.999:   "-1.00 kV",
+		-999.99:    "-999.99 V",
+		-0.9999999: "-1.00 V",
+		-0.999:     "-999.00 mV",
+		-1000:      "-1.00 kV",
+		-1e-6:      "-1.00 uV",
+		-5e9:       "-5.00 GV",
+	}
+	for v, want := range cases {
+		got, err := MarshalUnit("V", v)
+		if err != nil || string(got) != want {
+			t.Errorf("%v: got %q want %q (%v)", v, got, want, err)
+		}
+		back, err := UnmarshalUnit("V", got)
+		if err != nil || back >= 0 {
+			t.Errorf("%v: round trip %v %v", v, back, err)
+		}
+	}
+}
+
+func TestFusedExponent(t *testing.T) {
+	cases := []struct {
+		unit, text string
+		want       float64
+	}{
+		{"Hz", "1e3Hz", 1000},
+		{"V", "1E-3V", 0.001},
+		{"Hz", "1e3 Hz", 1000},
+		{"Hz", "2.5e+3kHz", 2.5e6},
+		{"m", "1e3m", 1000},
+		{"V", "1e-3mV", 1e-6},
+	}
+	for _, c := range cases {
+		got, err := UnmarshalUnit(c.unit, []byte(c.text))
+		if err != nil || got != c.want {
+			t.Errorf("%q: %v %v", c.text, got, err)
+		}
+	}
+	_, _, unit, err := Parse([]byte("1e3Hz"))
+	if err != nil || unit != "Hz" {
+		t.Error(unit, err)
+	}
+}
+
+func TestFusedExponentUnitE(t *testing.T) {
+	if v, err := UnmarshalUnit("eV", []byte("2eV")); err != nil || v != 2 {
+		t.Error(v, err)
+	}
+}
+
+func TestNiceStep(t *testing.T) {
+	cases := []struct {
+		max    float64
+		ticks  int
+		step   float64
+		prefix string
+	}{
+		{10000, 5, 2000, "k"},
+		{1500, 5, 500, "k"},
+		{7, 10, 1, ""},
+		{95, 10, 10, ""},
+		{0.0003, 5, 0.0001, "u"},
+		{3.3e-9, 4, 1e-9, "n"},
+		{1, 1, 1, ""},
+		{-42, 4, 20, ""},
+		{1e6, 3, 5e5, "M"},
+		{0, 5, 0, ""},
+		{10, 0, 0, ""},
+	}
+	for _, c := range cases {
+		step, prefix := NiceStep(c.max, c.ticks)
+		if step != c.step || prefix != c.prefix {
+			t.Errorf("%v/%d: got %v %q want %v %q", c.max, c.ticks, step, prefix, c.step, c.prefix)
+		}
+	}
+}
+
+func TestUnmarshalUnits(t *testing.T) {
+	v, err := UnmarshalUnits("V", [][]byte{[]byte("1 V"), []byte("3.3 mV"), []byte("2 kV")})
+	if err != nil || len(v) != 3 || v[0] != 1 || v[1] != 0.0033 || v[2] != 2000 {
+		t.Fatal(v, err)
+	}
+	_, err = UnmarshalUnits("V", [][]byte{[]byte("1 V"), []byte("1 XV"), []byte("bad")})
+	if !errors.Is(err, ErrUnknownPrefix) || !strings.HasPrefix(err.Error(), "row 1: ") {
+		t.Fatal(err)
+	}
+	if v, err := UnmarshalUnits("V", nil); err != nil || len(v) != 0 {
+		t.Fatal(v, err)
+	}
+}
+
+func TestNonThousandPrefixes(t *testing.T) {
+	cases := map[string]float64{"5 cm": 0.05, "3 dm": 0.3, "2 hm": 200, "4 dam": 40, "7 m": 7, "1 km": 1000}
+	for in, want := range cases {
+		got, err := UnmarshalUnit("m", []byte(in))
+		if err != nil || math.Abs(got-want) > 1e-12 {
+			t.Errorf("%q: %v %v", in, got, err)
+		}
+	}
+	if v, err := UnmarshalUnit("dB", []byte("3 dB")); err != nil || v != 3 {
+		t.Error(v, err)
+	}
+	if s, _ := MarshalUnit("m", 0.05); string(s) != "50.00 mm" {
+		t.Error(string(s))
+	}
+	if _, p, u, err := Parse([]byte("5 cd")); err != nil || p != "" || u != "cd" {
+		t.Error(p, u, err)
+	}
+}
+
+func TestMarshalUnitSig(t *testing.T) {
+	cases := []struct {
+		v    float64
+		sig  int
+		want string
+	}{
+		{12346, 4, "12.35 kHz"}, {12345, 4, "12.34 kHz"},
+		{0.00012346, 4, "123.5 uHz"},
+		{1.2345, 4, "1.234 Hz"},
+		{10, 4, "10.00 Hz"},
+		{999.96, 4, "1.000 kHz"},
+		{0, 3, "0.00 Hz"},
+		{-12345, 2, "-12 kHz"},
+		{123456, 2, "120 kHz"},
+		{10000, 1, "10 kHz"},
+	}
+	for _, c := range cases {
+		got, err := MarshalUnitSig("Hz", c.v, c.sig)
+		if err != nil || string(got) != c.want {
+			t.Errorf("%v/%d: %q want %q (%v)", c.v, c.sig, got, c.want, err)
+		}
+	}
+	if got, _ := MarshalUnitSig("%", 0.0512, 2); string(got) != "5.1 %" {
+		t.Error(string(got))
+	}
+	if _, err := MarshalUnitSig("Hz", 1, 0); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestFactor(t *testing.T) {
+	cases := map[string]float64{"m": 1e-3, "": 1, "k": 1e3, "G": 1e9, "u": 1e-6, "µ": 1e-6, "q": 1e-30, "Q": 1e30}
+	for p, want := range cases {
+		if f, ok := Factor(p); !ok || f != want {
+			t.Errorf("%q: %v %v", p, f, ok)
+		}
+	}
+	for _, p := range []string{"X", "Ki", "c", "mm"} {
+		if f, ok := Factor(p); ok || f != 0 {
+			t.Errorf("%q: %v %v", p, f, ok)
+		}
+	}
+}
+
+func TestParseLenient(t *testing.T) {
+	cases := []string{`"3.3 mV";`, `'3.3 mV'`, `*3.3 mV*`, ` "3.3mV" `, "`3.3 m*V`;;", `3.3 mV`}
+	for _, c := range cases {
+		v, err := ParseLenient("V", []byte(c))
+		if err != nil || v != 0.0033 {
+			t.Errorf("%q: %v %v", c, v, err)
+		}
+	}
+	if _, err := UnmarshalUnit("V", []byte(`"3.3 mV"`)); err == nil {
+		t.Error("strict parser should reject quotes")
+	}
+	if _, err := ParseLenient("V", []byte(`"3.3 #mV"`)); err == nil {
+		t.Error("expected error")
+	}
+	old := LenientJunk
+	defer func() { LenientJunk = old }()
+	LenientJunk = "#"
+	if v, err := ParseLenient("V", []byte(`3.3 #mV#`)); err != nil || v != 0.0033 {
+		t.Error(v, err)
+	}
+}
+
+func TestLowercaseKilo(t *testing.T) {
+	a, err1 := UnmarshalUnit("Hz", []byte("12 kHz"))
+	b, err2 := UnmarshalUnit("Hz", []byte("12 KHz"))
+	if err1 != nil || err2 != nil || a != 12000 || b != 12000 {
+		t.Fatal(a, b, err1, err2)
+	}
+	if s, _ := MarshalUnit("Hz", 12000); string(s) != "12.00 kHz" {
+		t.Fatal(string(s))
+	}
+	if _, p, u, err := Parse([]byte("12 KHz")); err != nil || p != "k" || u != "Hz" {
+		t.Fatal(p, u, err)
+	}
+	if s, err := MarshalUnitWithPrefix("Hz", "K", 12000, 2); err != nil || string(s) != "12.00 kHz" {
+		t.Fatal(string(s), err)
+	}
+	if v, err := ConvertPrefix(1, "K", "k"); err != nil || v != 1 {
+		t.Fatal(v, err)
+	}
+	if v, err := UnmarshalInt("B", []byte("2 KB")); err != nil || v != 2000 {
+		t.Fatal(v, err)
+	}
+	if v, err := UnmarshalBinary("B", []byte("1 KB")); err != nil || v != 1000 {
+		t.Fatal(v, err)
+	}
+	if v, err := UnmarshalBinary("B", []byte("1 KiB")); err != nil || v != 1024 {
+		t.Fatal(v, err)
+	}
+	f := DefaultFormatter
+	f.PrefixAlias = map[string]string{"k": "K"}
+	if s := f.Format("Hz", 12000); s != "12.00 KHz" {
+		t.Fatal(s)
+	}
+	RegisterUnit("legacyK", []string{"", "K"}, []int64{0, 3})
+	if s, _ := MarshalUnit("legacyK", 2000); string(s) != "2.00 KlegacyK" {
+		t.Fatal(string(s))
+	}
+	if v, err := UnmarshalUnit("legacyK", []byte("2 KlegacyK")); err != nil || v != 2000 {
+		t.Fatal(v, err)
+	}
+}
+
+func TestUnmarshalUnitExact(t *testing.T) {
+	for _, in := range []string{"12 kHz", "12 KHz", "12kHz"} {
+		if v, err := UnmarshalUnitExact("Hz", "k", []byte(in)); err != nil || v != 12000 {
+			t.Error(in, v, err)
+		}
+	}
+	if _, err := UnmarshalUnitExact("Hz", "k", []byte("12 MHz")); !errors.Is(err, ErrPrefixMismatch) {
+		t.Error(err)
+	}
+	if _, err := UnmarshalUnitExact("Hz", "k", []byte("12000 Hz")); !errors.Is(err, ErrPrefixMismatch) {
+		t.Error(err)
+	}
+	if v, err := UnmarshalUnitExact("Hz", "", []byte("12 Hz")); err != nil || v != 12 {
+		t.Error(v, err)
+	}
+	if v, err := UnmarshalUnitExact("V", "u", []byte("3 µV")); err != nil || v != 3e-6 {
+		t.Error(v, err)
+	}
+	if v, err := UnmarshalUnitExact("m", "c", []byte("5 cm")); err != nil || v != 0.05 {
+		t.Error(v, err)
+	}
+	if _, err := UnmarshalUnitExact("Hz", "x", []byte("12 Hz")); !errors.Is(err, ErrUnknownPrefix) {
+		t.Error(err)
+	}
+	if _, err := UnmarshalUnitExact("Hz", "k", []byte("12 V")); !errors.Is(err, ErrUnitMismatch) {
+		t.Error(err)
+	}
+	if v, err := UnmarshalUnitExact("%", "", []byte("50 %")); err != nil || v != 0.5 {
+		t.Error(v, err)
+	}
+}
+
+func TestMarshalRate(t *testing.T) {
+	cases := []struct {
+		num, den string
+		v        float64
+		want     string
+	}{
+		{"m", "s", 12000, "12.00 km/s"},
+		{"J", "K", 0.0042, "4.20 mJ/K"},
+		{"J", "K", 4200, "4.20 kJ/K"},
+		{"m", "s^2", 9.8, "9.80 m/s^2"},
+		{"B", "s", 1.5e6, "1.50 MB/s"},
+	}
+	for _, c := range cases {
+		got, err := MarshalRate(c.num, c.den, c.v)
+		if err != nil || string(got) != c.want {
+			t.Errorf("%v: %q want %q", c.v, got, c.want)
+		}
+	}
+	RegisterUnit("cnt", []string{"", "k"}, []int64{0, 3})
+	if got, _ := MarshalRate("cnt", "ms", 5e3); string(got) != "5.00 kcnt/ms" {
+		t.Error(string(got))
+	}
+	if _, err := MarshalRate("cnt", "ms", 5e6); err == nil {
+		t.Error("expected out of range")
+	}
+}
+
+func TestExplain(t *testing.T) {
+	if e := Explain("Hz", 12000); e != "value 12000: order=3 prefix=k mantissa=12 -> '12.00 kHz'" {
+		t.Error(e)
+	}
+	e := Explain("Hz", 999.999)
+	for _, want := range []string{"order=3", "prefix=k", "mantissa=0.999999", "'1.00 kHz'"} {
+		if !strings.Contains(e, want) {
+			t.Error(e, want)
+		}
+	}
+	if e := Explain("V", 0.0033); !strings.Contains(e, "order=-3 prefix=m mantissa=3.3") {
+		t.Error(e)
+	}
+	if e := Explain("V", 1e40); !strings.Contains(e, "error: value out of range") {
+		t.Error(e)
+	}
+}
+
+func TestEmptyUnit(t *testing.T) {
+	if _, err := MarshalUnit("", 12000); !errors.Is(err, ErrEmptyUnit) {
+		t.Error(err)
+	}
+	if _, err := MarshalUnitPrec("", 1, 2); !errors.Is(err, ErrEmptyUnit) {
+		t.Error(err)
+	}
+	if _, err := MarshalUnitWithPrefix("", "k", 1, 2); !errors.Is(err, ErrEmptyUnit) {
+		t.Error(err)
+	}
+	if b, err := AppendUnit([]byte("x"), "", 1); !errors.Is(err, ErrEmptyUnit) || string(b) != "x" {
+		t.Error(err)
+	}
+	if _, err := MarshalUnitSig("", 12345, 4); !errors.Is(err, ErrEmptyUnit) {
+		t.Error(err)
+	}
+	if _, err := MarshalUnitSigFigs("", 12345, 4); !errors.Is(err, ErrEmptyUnit) {
+		t.Error(err)
+	}
+	if _, err := MarshalRate("", "s", 12000); !errors.Is(err, ErrEmptyUnit) {
+		t.Error(err)
+	}
+	if _, err := MarshalRate("m", "", 12000); !errors.Is(err, ErrEmptyUnit) {
+		t.Error(err)
+	}
+	if _, err := MarshalBinary("", 1024); !errors.Is(err, ErrEmptyUnit) {
+		t.Error(err)
+	}
+	if s := Sprint("", 12000); s != "12.00 k" {
+		t.Errorf("Sprint: %q", s)
+	}
+	if s := Sprint("", math.NaN()); s != "NaN" {
+		t.Errorf("Sprint: %q", s)
+	}
+	cases := map[float64]string{12000: "12.00 k", 3.3: "3.30", 0.0047: "4.70 m", 0: "0.00"}
+	for v, want := range cases {
+		if got, err := MarshalScalar(v); err != nil || string(got) != want {
+			t.Errorf("%v: %q %v", v, got, err)
+		}
+	}
+	if _, err := MarshalScalar(1e40); !errors.Is(err, ErrOutOfRange) {
+		t.Error(err)
+	}
+}
+
+func TestParseUncertain(t *testing.T) {
+	cases := []struct {
+		text string
+		v, u float64
+	}{
+		{"3.3 ± 0.1 V", 3.3, 0.1},
+		{"3.3 +/- 0.1 V", 3.3, 0.1},
+		{"3.3±0.1V", 3.3, 0.1},
+		{"3.3 ± 0.1 mV", 3.3e-3, 0.1e-3},
+		{"3.3 mV ± 100 uV", 3.3e-3, 100e-6},
+		{"-1.5e3 +/- 2 V", -1500, 2},
+		{"3.3 V", 3.3, 0},
+		{"3.3 kV", 3300, 0},
+	}
+	for _, c := range cases {
+		v, u, err := ParseUncertain("V", []byte(c.text))
+		if err != nil || !(Unit{"V", v}).EqualTol(Unit{"V", c.v}, 1e-12) || !(Unit{"V", u}).EqualTol(Unit{"V", c.u}, 1e-12) {
+			t.Errorf("%q: %v %v %v", c.text, v, u, err)
+		}
+	}
+	for _, text := range []string{"3.3 ± -0.1 V", "3.3 ± V", "± 0.1 V", "x ± 0.1 V"} {
+		if _, _, err := ParseUncertain("V", []byte(text)); !errors.Is(err, ErrMalformedValue) {
+			t.Errorf("%q: %v", text, err)
+		}
+	}
+	if _, _, err := ParseUncertain("V", []byte("3.3 ± 0.1 A")); !errors.Is(err, ErrUnitMismatch) {
+		t.Error(err)
+	}
+}
+
+func TestSignedExponents(t *testing.T) {
+	cases := []struct {
+		text string
+		want float64
+	}{
+		{"-1.5e-3 V", -0.0015},
+		{"-1.5e+3 V", -1500},
+		{"+1.5e-3 V", 0.0015},
+		{"+1.5e+3 V", 1500},
+		{"-1.5e-3V", -0.0015},
+		{"+1.5E+3V", 1500},
+		{"-1.5e-3 kV", -1.5},
+		{"+1.5e+3 mV", 1.5},
+		{"-15e-4 V", -0.0015},
+	}
+	for _, c := range cases {
+		for _, f := range []func(string, []byte) (float64, error){UnmarshalUnit, UnmarshalValue} {
+			got, err := f("V", []byte(c.text))
+			if err != nil || !(Unit{"V", got}).EqualTol(Unit{"V", c.want}, 1e-15) {
+				t.Errorf("%q: %v %v", c.text, got, err)
+			}
+		}
+		v, _, u, err := Parse([]byte(c.text))
+		if err != nil || u != "V" || !(Unit{"V", v}).EqualTol(Unit{"V", c.want}, 1e-15) {
+			t.Errorf("Parse %q: %v %v %v", c.text, v, u, err)
+		}
+	}
+	for _, text := range []string{"-+1.5e3 V", "1.5e+-3 V", "--1.5 V"} {
+		if _, err := UnmarshalUnit("V", []byte(text)); err == nil {
+			t.Errorf("%q: expected error", text)
+		}
+	}
+	if v, err := UnmarshalValue("V", []byte("-1.5e-3")); err != nil || v != -0.0015 {
+		t.Error(v, err)
+	}
+}
+
+func TestMarshalCount(t *testing.T) {
+	cases := []struct {
+		value float64
+		want  string
+	}{
+		{0, "0.00"},
+		{1, "1.00"},
+		{999, "999.00"},
+		{0.5, "0.50"},
+		{0.001, "0.00"},
+		{1200, "1.20 k"},
+		{1.2e6, "1.20 M"},
+		{3.4e9, "3.40 G"},
+		{-1.2e6, "-1.20 M"},
+		{999999, "1.00 M"},
+	}
+	for _, c := range cases {
+		got, err := MarshalCount(c.value)
+		if err != nil || string(got) != c.want {
+			t.Errorf("%v: %q %v", c.value, got, err)
+		}
+	}
+	if _, err := MarshalCount(math.NaN()); err == nil {
+		t.Error("expected error")
+	}
+}